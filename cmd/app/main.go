@@ -16,17 +16,27 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	_ "person-info/docs"
-	"person-info/internal/client/person/agify"
-	"person-info/internal/client/person/genderize"
-	"person-info/internal/client/person/nationalize"
+	"person-info/internal/app"
 	"person-info/internal/config"
 	"person-info/internal/lib/logger/sl"
+	overrideService "person-info/internal/service/override"
 	personService "person-info/internal/service/person"
 	"person-info/internal/storage/postgres"
+	"person-info/internal/transport/handler/cache/invalidate"
+	"person-info/internal/transport/handler/cache/stats"
+	"person-info/internal/transport/handler/health"
+	overrideDelete "person-info/internal/transport/handler/override/delete"
+	overrideList "person-info/internal/transport/handler/override/list"
+	overrideSave "person-info/internal/transport/handler/override/save"
 	"person-info/internal/transport/handler/person/create"
 	del "person-info/internal/transport/handler/person/delete"
+	"person-info/internal/transport/handler/person/enrich"
+	"person-info/internal/transport/handler/person/provenance"
 	"person-info/internal/transport/handler/person/read"
+	"person-info/internal/transport/handler/person/retry"
 	"person-info/internal/transport/handler/person/update"
+	"person-info/internal/transport/handler/prediction/preview"
+	"person-info/internal/transport/handler/provider/usage"
 	healthchecker "person-info/internal/transport/middleware/health-checker"
 )
 
@@ -57,7 +67,7 @@ func main() {
 	)
 	defer cancel()
 
-	dbURL := postgresURL(cfg)
+	dbURL := app.PostgresURL(cfg)
 
 	log.Debug("connecting to postgres", slog.String("url", dbURL))
 
@@ -66,30 +76,73 @@ func main() {
 		panic(err)
 	}
 
-	ageClient := agify.New(log)
-	genderClient := genderize.New(log)
-	nationClient := nationalize.New(log)
+	providers := app.MustProviders(log, cfg, storage)
 
 	service := personService.New(log,
 		storage,
-		ageClient,
-		genderClient,
-		nationClient,
+		providers.Age,
+		providers.Gender,
+		providers.Nationality,
+		cfg.Enrichment,
 	)
 
+	overrides := overrideService.New(log, storage)
+
+	if providers.Learned != nil {
+		providers.Learned.Start(ctx, cfg.Providers.Learned.RefreshInterval)
+	}
+
+	if err := service.Start(ctx); err != nil {
+		panic(err)
+	}
+
 	g := gin.New()
 
 	g.Use(gin.Recovery())
+
+	g.GET("/health", health.New(log, storage, map[string]health.CircuitStateProvider{
+		"agify":       providers.AgeClient,
+		"genderize":   providers.GenderClient,
+		"nationalize": providers.NationalityClient,
+	}))
+
 	g.Use(healthchecker.New(log, storage))
 
 	g.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	peopleGroup := g.Group("/people")
 	{
-		peopleGroup.POST("/", create.New(ctx, log, service))
+		if cfg.Enrichment.Async {
+			peopleGroup.POST("/", create.NewAsync(ctx, log, service))
+		} else {
+			peopleGroup.POST("/", create.New(ctx, log, service))
+		}
+		peopleGroup.POST("/batch", create.NewBatch(ctx, log, service))
 		peopleGroup.GET("/", read.New(ctx, log, service))
+		peopleGroup.GET("/:id", read.NewPerson(ctx, log, service))
 		peopleGroup.PATCH("/:id", update.New(ctx, log, service))
 		peopleGroup.DELETE("/:id", del.New(ctx, log, service))
+		peopleGroup.POST("/:id/retry", retry.New(ctx, log, service))
+		peopleGroup.POST("/:id/enrich", enrich.New(ctx, log, service))
+		peopleGroup.POST("/enrich", enrich.NewBulk(ctx, log, service))
+		peopleGroup.GET("/:id/provenance", provenance.New(ctx, log, service))
+	}
+
+	g.GET("/predictions", preview.New(ctx, log, service))
+
+	adminGroup := g.Group("/admin")
+	{
+		adminGroup.GET("/cache", stats.New(ctx, log, providers.PredictionCache))
+		adminGroup.DELETE("/cache", invalidate.New(ctx, log, providers.MemoryCache, providers.PredictionCache))
+		adminGroup.DELETE("/cache/:name", invalidate.New(ctx, log, providers.MemoryCache, providers.PredictionCache))
+		adminGroup.GET("/providers", usage.New(ctx, log, map[string]usage.UsageProvider{
+			"agify":       providers.AgeClient,
+			"genderize":   providers.GenderClient,
+			"nationalize": providers.NationalityClient,
+		}))
+		adminGroup.GET("/overrides", overrideList.New(ctx, log, overrides))
+		adminGroup.PUT("/overrides/:name/:field", overrideSave.New(ctx, log, overrides))
+		adminGroup.DELETE("/overrides/:name/:field", overrideDelete.New(ctx, log, overrides))
 	}
 
 	srvAddr := serverAddr(cfg)
@@ -126,6 +179,8 @@ func main() {
 		log.Error("failed to shutdown server", sl.Err(err))
 	}
 
+	service.Wait()
+
 	if err := storage.Close(shutdownCtx); err != nil {
 		log.Error("failed to shutdown storage", sl.Err(err))
 	}
@@ -136,8 +191,3 @@ func main() {
 func serverAddr(cfg *config.Config) string {
 	return fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
 }
-
-func postgresURL(cfg *config.Config) string {
-	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		cfg.DB.User, cfg.DB.Password, cfg.DB.Host, cfg.DB.Port, cfg.DB.Name)
-}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"person-info/internal/app"
+	"person-info/internal/config"
+	"person-info/internal/lib/logger/sl"
+	personService "person-info/internal/service/person"
+	"person-info/internal/storage/postgres"
+	"person-info/internal/transport/dto"
+)
+
+// enrich re-runs the prediction providers for stored people and prints the changes as JSON.
+// A single person is selected with -id, otherwise people are selected by the filters.
+func main() {
+	var (
+		id            int64
+		filters       dto.PeopleFilters
+		pagination    dto.Pagination
+		lowConfidence string
+		force         bool
+	)
+
+	flag.Int64Var(&id, "id", 0, "person id")
+	flag.StringVar(&filters.Name, "name", "", "name filter")
+	flag.StringVar(&filters.Surname, "surname", "", "surname filter")
+	flag.StringVar(&filters.Gender, "gender", "", "gender filter")
+	flag.StringVar(&filters.Nationality, "nationality", "", "nationality filter")
+	flag.StringVar(&filters.EnrichmentStatus, "status", "", "enrichment status filter")
+	flag.StringVar(&lowConfidence, "low-confidence", "", "low confidence filter, true or false")
+	flag.BoolVar(&force, "force", false, "overwrite attributes set by hand")
+	flag.IntVar(&pagination.Page, "page", 0, "page number")
+	flag.IntVar(&pagination.Size, "size", 0, "page size, all matching people if 0")
+
+	cfg := config.MustLoad()
+
+	log := slog.New(
+		slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}),
+	)
+
+	if lowConfidence != "" {
+		v, err := strconv.ParseBool(lowConfidence)
+		if err != nil {
+			panic("invalid low-confidence filter: " + err.Error())
+		}
+		filters.LowConfidence = &v
+	}
+
+	ctx, cancel := signal.NotifyContext(
+		context.Background(),
+		os.Interrupt,
+		syscall.SIGTERM,
+		syscall.SIGINT,
+	)
+	defer cancel()
+
+	storage, err := postgres.New(app.PostgresURL(cfg))
+	if err != nil {
+		panic(err)
+	}
+	defer storage.Close(context.Background())
+
+	providers := app.MustProviders(log, cfg, storage)
+
+	service := personService.New(log,
+		storage,
+		providers.Age,
+		providers.Gender,
+		providers.Nationality,
+		cfg.Enrichment,
+	)
+
+	var result any
+	if id > 0 {
+		result, err = service.Enrich(ctx, id, force)
+	} else {
+		result, err = service.EnrichPeople(ctx, &filters, &pagination, &dto.SortOptions{By: "id"}, force)
+	}
+	if err != nil {
+		log.Error("failed to re-enrich", sl.Err(err))
+		os.Exit(1)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(result); err != nil {
+		panic(err)
+	}
+}
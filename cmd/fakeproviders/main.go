@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/client/person/offline"
+	"person-info/internal/fakeproviders"
+	"person-info/internal/lib/logger/sl"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// fakeproviders serves agify, genderize and nationalize compatible endpoints
+// at /agify, /genderize and /nationalize for local development
+func main() {
+	var (
+		addr    string
+		dataset string
+		opts    fakeproviders.Options
+	)
+
+	flag.StringVar(&addr, "addr", ":8081", "listen address")
+	flag.StringVar(&dataset, "dataset", "data/names.csv", "path to the name dataset")
+	flag.Uint64Var(&opts.Seed, "seed", 1, "seed for generated predictions and injected faults")
+	flag.BoolVar(&opts.Generate, "generate", false, "generate predictions for names missing from the dataset")
+	flag.IntVar(&opts.Quota, "quota", 0, "names per provider per day, 0 means unlimited")
+	flag.IntVar(&opts.Faults.LatencyMS, "latency-ms", 0, "delay before every response")
+	flag.Float64Var(&opts.Faults.ErrorRate, "error-rate", 0, "share of requests answered with 503")
+	flag.Float64Var(&opts.Faults.RateLimitRate, "rate-limit-rate", 0, "share of requests answered with 429")
+	flag.Float64Var(&opts.Faults.NullRate, "null-rate", 0, "share of requests answered with null predictions")
+	flag.Parse()
+
+	log := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	ctx, cancel := signal.NotifyContext(
+		context.Background(),
+		os.Interrupt,
+		syscall.SIGTERM,
+		syscall.SIGINT,
+	)
+	defer cancel()
+
+	provider, err := offline.Load(log, dataset)
+	if err != nil {
+		panic(err)
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: fakeproviders.New(log, provider, opts).Handler(),
+	}
+
+	go func() {
+		log.Info("starting fake providers", slog.String("addr", addr))
+
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("failed to start server", sl.Err(err))
+			cancel()
+		}
+	}()
+
+	<-ctx.Done()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Error("failed to shutdown server", sl.Err(err))
+	}
+}
@@ -15,6 +15,274 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/cache": {
+            "get": {
+                "description": "Returns cached entries count and hit/miss counters per prediction kind",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "/admin"
+                ],
+                "summary": "Get prediction cache stats",
+                "responses": {
+                    "200": {
+                        "description": "Cache stats",
+                        "schema": {
+                            "$ref": "#/definitions/cache.Stats"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/cache/{name}": {
+            "delete": {
+                "description": "Removes cached predictions for a name, or the whole cache when name is omitted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "/admin"
+                ],
+                "summary": "Invalidate prediction cache",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
+                        "in": "path"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cache invalidated",
+                        "schema": {
+                            "$ref": "#/definitions/dto.InvalidateCacheResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/overrides": {
+            "get": {
+                "description": "Returns the overrides for a name, or all of them when name is omitted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "/admin"
+                ],
+                "summary": "List name overrides",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Name overrides",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.NameOverrideResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/overrides/{name}/{field}": {
+            "put": {
+                "description": "Creates or replaces the answer given for a name ahead of the external providers.\nThe override is pinned, edits of people with the name no longer change it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "/admin"
+                ],
+                "summary": "Set a name override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "age",
+                            "gender",
+                            "nationality"
+                        ],
+                        "type": "string",
+                        "description": "Field",
+                        "name": "field",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Override value",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SaveOverrideRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Saved override",
+                        "schema": {
+                            "$ref": "#/definitions/dto.NameOverrideResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid field or value",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes an override, the name is answered by the providers again",
+                "tags": [
+                    "/admin"
+                ],
+                "summary": "Delete a name override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "age",
+                            "gender",
+                            "nationality"
+                        ],
+                        "type": "string",
+                        "description": "Field",
+                        "name": "field",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Override deleted"
+                    },
+                    "404": {
+                        "description": "Override not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/providers": {
+            "get": {
+                "description": "Reports today's quota usage of each prediction provider, a degraded provider is skipped until its quota resets",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "/admin"
+                ],
+                "summary": "Provider usage",
+                "responses": {
+                    "200": {
+                        "description": "Usage per provider",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProviderUsageResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Reports postgres availability and circuit breaker state of each prediction provider",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "/health"
+                ],
+                "summary": "Service health",
+                "responses": {
+                    "200": {
+                        "description": "Service is healthy or degraded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/people": {
             "get": {
                 "description": "Get people using filters and pagination",
@@ -34,6 +302,17 @@ const docTemplate = `{
                         "name": "age",
                         "in": "query"
                     },
+                    {
+                        "enum": [
+                            "pending",
+                            "done",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "example": "failed",
+                        "name": "enrichment_status",
+                        "in": "query"
+                    },
                     {
                         "enum": [
                             "male",
@@ -44,6 +323,13 @@ const docTemplate = `{
                         "name": "gender",
                         "in": "query"
                     },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "LowConfidence selects people with or without attributes predicted below the thresholds",
+                        "name": "low_confidence",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "example": "John",
@@ -150,6 +436,24 @@ const docTemplate = `{
                         "description": "Successfully saved person",
                         "schema": {
                             "$ref": "#/definitions/dto.PersonResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the person, /people/{id}"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Person accepted for background enrichment in async mode",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AcceptedResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the person, /people/{id}"
+                            }
                         }
                     },
                     "400": {
@@ -164,55 +468,363 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "422": {
+                        "description": "Invalid name or prediction confidence is too low",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Prediction provider rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/people/{id}": {
-            "delete": {
-                "description": "Deletes a person by person id",
-                "tags": [
-                    "/people"
-                ],
-                "summary": "Delete a person",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Person ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "Person deleted successfully"
                     },
-                    "400": {
-                        "description": "Missing or invalid id",
+                    "502": {
+                        "description": "Prediction provider failed",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Person not found",
+                    "503": {
+                        "description": "Prediction provider unavailable",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "504": {
+                        "description": "Prediction provider timed out",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/people/batch": {
+            "post": {
+                "description": "Saves up to 100 people, predictions for distinct names are fetched in batches.\nEvery person gets its own status, a failure to save one person does not undo the others.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "/people"
+                ],
+                "summary": "Save people in bulk",
+                "parameters": [
+                    {
+                        "description": "People request data",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePeopleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-person results",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.BatchPersonResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Prediction provider rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Prediction provider failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Prediction provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Prediction provider timed out",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/people/enrich": {
+            "post": {
+                "description": "Re-runs the prediction providers for a page of people selected like in GET /people.\nThe page size is required and at most 100.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "/people"
+                ],
+                "summary": "Re-enrich people",
+                "parameters": [
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 30,
+                        "name": "age",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "done",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "example": "failed",
+                        "name": "enrichment_status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "male",
+                            "female"
+                        ],
+                        "type": "string",
+                        "example": "male",
+                        "name": "gender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "LowConfidence selects people with or without attributes predicted below the thresholds",
+                        "name": "low_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "John",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "RU",
+                        "name": "nationality",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Dmitrich",
+                        "name": "patronymic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Snow",
+                        "name": "surname",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 10,
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "surname",
+                            "age"
+                        ],
+                        "type": "string",
+                        "example": "name",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Force overwrites attributes set by hand",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changed attributes per person",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkEnrichmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filters or missing page size",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Prediction provider rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Prediction provider failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Prediction provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Prediction provider timed out",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/people/{id}": {
+            "get": {
+                "description": "Gets a person by id with prediction confidence, provenance and links to related resources",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "/people"
+                ],
+                "summary": "Get a person",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Person details",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PersonDetailsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a person by person id",
+                "tags": [
+                    "/people"
+                ],
+                "summary": "Delete a person",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Person deleted successfully"
+                    },
+                    "400": {
+                        "description": "Missing or invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "patch": {
                 "description": "Updates a person by id",
                 "consumes": [
@@ -224,7 +836,63 @@ const docTemplate = `{
                 "tags": [
                     "/people"
                 ],
-                "summary": "Update a person",
+                "summary": "Update a person",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update fields",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePersonRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated person",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PersonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/people/{id}/enrich": {
+            "post": {
+                "description": "Re-runs the prediction providers for a person, attributes edited by hand are kept unless forced",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "/people"
+                ],
+                "summary": "Re-enrich a person",
                 "parameters": [
                     {
                         "type": "integer",
@@ -234,24 +902,140 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Update fields",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Force overwrites attributes set by hand",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changed attributes",
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdatePersonRequest"
+                            "$ref": "#/definitions/dto.EnrichmentDiffResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Prediction provider rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Prediction provider failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Prediction provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
+                    },
+                    "504": {
+                        "description": "Prediction provider timed out",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/people/{id}/provenance": {
+            "get": {
+                "description": "Tells the source, confidence and time each predicted attribute was set with",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "/people"
+                ],
+                "summary": "Get person provenance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated person",
+                        "description": "Provenance per attribute",
                         "schema": {
-                            "$ref": "#/definitions/dto.PersonResponse"
+                            "$ref": "#/definitions/dto.ProvenanceResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Missing or invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/people/{id}/retry": {
+            "post": {
+                "description": "Queues enrichment of a person whose enrichment has failed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "/people"
+                ],
+                "summary": "Retry failed enrichment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Enrichment queued",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AcceptedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid id",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -262,17 +1046,238 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Enrichment has not failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/predictions": {
+            "get": {
+                "description": "Predicts age, gender and nationality like saving a person would, without saving anything",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "/predictions"
+                ],
+                "summary": "Preview predictions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Surname",
+                        "name": "surname",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Patronymic",
+                        "name": "patronymic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country hint, ISO 3166-1 alpha-2",
+                        "name": "country_hint",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Predicted attributes",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PredictionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid name",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Prediction provider rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
+                    },
+                    "502": {
+                        "description": "Prediction provider failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Prediction provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Prediction provider timed out",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "cache.KindStats": {
+            "type": "object",
+            "properties": {
+                "hits": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cache.Stats": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "integer"
+                },
+                "kinds": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/cache.KindStats"
+                    }
+                }
+            }
+        },
+        "dto.AcceptedResponse": {
+            "type": "object",
+            "properties": {
+                "enrichment_status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "dto.AgePredictionResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1247
+                },
+                "ensemble": {
+                    "$ref": "#/definitions/dto.EnsembleResponse"
+                },
+                "low_confidence": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "source": {
+                    "type": "string",
+                    "example": "agify"
+                }
+            }
+        },
+        "dto.BatchPersonResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "person already exists"
+                },
+                "person": {
+                    "$ref": "#/definitions/dto.PersonResponse"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 201
+                }
+            }
+        },
+        "dto.BulkEnrichmentResponse": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "people": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.EnrichmentDiffResponse"
+                    }
+                },
+                "processed": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "dto.CountryPredictionResponse": {
+            "type": "object",
+            "properties": {
+                "country_id": {
+                    "type": "string",
+                    "example": "RU"
+                },
+                "probability": {
+                    "type": "number",
+                    "example": 0.43
+                }
+            }
+        },
+        "dto.CreatePeopleRequest": {
+            "type": "object",
+            "required": [
+                "people"
+            ],
+            "properties": {
+                "people": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.CreatePersonRequest"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
+        },
         "dto.CreatePersonRequest": {
             "type": "object",
             "required": [
@@ -280,6 +1285,11 @@ const docTemplate = `{
                 "surname"
             ],
             "properties": {
+                "country_hint": {
+                    "description": "CountryHint localizes age and gender predictions, ISO 3166-1 alpha-2",
+                    "type": "string",
+                    "example": "RU"
+                },
                 "name": {
                     "type": "string",
                     "example": "John"
@@ -294,6 +1304,78 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.EnrichmentDiffResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FieldChangeResponse"
+                    }
+                },
+                "error": {
+                    "type": "string",
+                    "example": "invalid name"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "kept": {
+                    "description": "Kept lists attributes edited by hand that were left alone",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "gender"
+                    ]
+                }
+            }
+        },
+        "dto.EnsembleMemberResponse": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string",
+                    "example": "female"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "probability": {
+                    "type": "number",
+                    "example": 0.91
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "genderize"
+                },
+                "share": {
+                    "type": "number",
+                    "example": 0.67
+                },
+                "weight": {
+                    "type": "number",
+                    "example": 2
+                }
+            }
+        },
+        "dto.EnsembleResponse": {
+            "type": "object",
+            "properties": {
+                "disagreement": {
+                    "type": "number",
+                    "example": 0.25
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.EnsembleMemberResponse"
+                    }
+                }
+            }
+        },
         "dto.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -303,6 +1385,236 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.FieldChangeResponse": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "age"
+                },
+                "new": {
+                    "type": "string",
+                    "example": "43"
+                },
+                "old": {
+                    "type": "string",
+                    "example": "41"
+                }
+            }
+        },
+        "dto.FieldProvenanceResponse": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "type": "number",
+                    "example": 0.98
+                },
+                "source": {
+                    "type": "string",
+                    "example": "genderize"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-01-01T12:00:00Z"
+                }
+            }
+        },
+        "dto.GenderPredictionResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1247
+                },
+                "ensemble": {
+                    "$ref": "#/definitions/dto.EnsembleResponse"
+                },
+                "low_confidence": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "probability": {
+                    "type": "number",
+                    "example": 0.98
+                },
+                "source": {
+                    "type": "string",
+                    "example": "genderize"
+                }
+            }
+        },
+        "dto.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "providers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "storage": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "dto.InvalidateCacheResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "dto.NameOverrideResponse": {
+            "type": "object",
+            "properties": {
+                "corrections": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "field": {
+                    "type": "string",
+                    "example": "gender"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "sasha"
+                },
+                "pinned": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-01-01T12:00:00Z"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "female"
+                }
+            }
+        },
+        "dto.NationalityPredictionResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1247
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CountryPredictionResponse"
+                    }
+                },
+                "ensemble": {
+                    "$ref": "#/definitions/dto.EnsembleResponse"
+                },
+                "low_confidence": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "source": {
+                    "type": "string",
+                    "example": "nationalize"
+                }
+            }
+        },
+        "dto.PersonDetailsResponse": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-01T12:00:00Z"
+                },
+                "enrichment_error": {
+                    "type": "string",
+                    "example": "age provider: invalid name"
+                },
+                "enrichment_status": {
+                    "description": "EnrichmentStatus is pending until background enrichment is done or failed",
+                    "type": "string",
+                    "example": "done"
+                },
+                "gender": {
+                    "type": "string",
+                    "example": "Male"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "links": {
+                    "$ref": "#/definitions/dto.PersonLinksResponse"
+                },
+                "low_confidence": {
+                    "description": "LowConfidence is set when any attribute was predicted below the thresholds and stored as unknown",
+                    "type": "boolean",
+                    "example": false
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Matvey"
+                },
+                "nationality": {
+                    "type": "string",
+                    "example": "RU"
+                },
+                "patronymic": {
+                    "type": "string",
+                    "example": "Dmitrievich"
+                },
+                "predictions": {
+                    "$ref": "#/definitions/dto.PredictionsResponse"
+                },
+                "provenance": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/dto.FieldProvenanceResponse"
+                    }
+                },
+                "surname": {
+                    "type": "string",
+                    "example": "Likhanov"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-01-01T12:00:00Z"
+                }
+            }
+        },
+        "dto.PersonLinksResponse": {
+            "type": "object",
+            "properties": {
+                "enrich": {
+                    "type": "string",
+                    "example": "/people/42/enrich"
+                },
+                "provenance": {
+                    "type": "string",
+                    "example": "/people/42/provenance"
+                },
+                "retry": {
+                    "description": "Retry is set while the background enrichment has failed",
+                    "type": "string",
+                    "example": "/people/42/retry"
+                },
+                "self": {
+                    "type": "string",
+                    "example": "/people/42"
+                }
+            }
+        },
         "dto.PersonResponse": {
             "type": "object",
             "properties": {
@@ -310,10 +1622,32 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 20
                 },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-01T12:00:00Z"
+                },
+                "enrichment_error": {
+                    "type": "string",
+                    "example": "age provider: invalid name"
+                },
+                "enrichment_status": {
+                    "description": "EnrichmentStatus is pending until background enrichment is done or failed",
+                    "type": "string",
+                    "example": "done"
+                },
                 "gender": {
                     "type": "string",
                     "example": "Male"
                 },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "low_confidence": {
+                    "description": "LowConfidence is set when any attribute was predicted below the thresholds and stored as unknown",
+                    "type": "boolean",
+                    "example": false
+                },
                 "name": {
                     "type": "string",
                     "example": "Matvey"
@@ -326,9 +1660,130 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Dmitrievich"
                 },
+                "predictions": {
+                    "$ref": "#/definitions/dto.PredictionsResponse"
+                },
                 "surname": {
                     "type": "string",
                     "example": "Likhanov"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-01-01T12:00:00Z"
+                }
+            }
+        },
+        "dto.PredictionResponse": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer",
+                    "example": 34
+                },
+                "gender": {
+                    "type": "string",
+                    "example": "male"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John"
+                },
+                "nationality": {
+                    "type": "string",
+                    "example": "RU"
+                },
+                "patronymic": {
+                    "type": "string",
+                    "example": "Dmitrievich"
+                },
+                "predictions": {
+                    "$ref": "#/definitions/dto.PredictionsResponse"
+                },
+                "surname": {
+                    "type": "string",
+                    "example": "Snow"
+                }
+            }
+        },
+        "dto.PredictionsResponse": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "$ref": "#/definitions/dto.AgePredictionResponse"
+                },
+                "gender": {
+                    "$ref": "#/definitions/dto.GenderPredictionResponse"
+                },
+                "nationality": {
+                    "$ref": "#/definitions/dto.NationalityPredictionResponse"
+                }
+            }
+        },
+        "dto.ProvenanceResponse": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/dto.FieldProvenanceResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "dto.ProviderUsageResponse": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "circuit_state": {
+                    "type": "string",
+                    "example": "closed"
+                },
+                "day": {
+                    "type": "string",
+                    "example": "2025-01-01"
+                },
+                "degraded": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "left": {
+                    "type": "integer",
+                    "example": 43
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "names": {
+                    "type": "integer",
+                    "example": 57
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "agify"
+                },
+                "reset_at": {
+                    "type": "string",
+                    "example": "2025-01-02T00:00:00Z"
+                }
+            }
+        },
+        "dto.SaveOverrideRequest": {
+            "type": "object",
+            "required": [
+                "value"
+            ],
+            "properties": {
+                "value": {
+                    "type": "string",
+                    "example": "female"
                 }
             }
         },
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/client/person/agify"
+	"person-info/internal/client/person/cache"
+	"person-info/internal/client/person/chain"
+	"person-info/internal/client/person/ensemble"
+	"person-info/internal/client/person/genderize"
+	"person-info/internal/client/person/inmemory"
+	"person-info/internal/client/person/learned"
+	"person-info/internal/client/person/nationalize"
+	"person-info/internal/client/person/offline"
+	"person-info/internal/client/person/override"
+	"person-info/internal/client/person/rules"
+	"person-info/internal/config"
+	"person-info/internal/storage/postgres"
+)
+
+const (
+	offlineProvider  = "offline"
+	overrideProvider = "override"
+	rulesProvider    = "rules"
+	ensembleProvider = "ensemble"
+	learnedProvider  = "learned"
+)
+
+// Providers is the prediction pipeline shared by the API server and the command line tools
+type Providers struct {
+	AgeClient         *agify.Client
+	GenderClient      *genderize.Client
+	NationalityClient *nationalize.Client
+
+	PredictionCache *cache.Cache
+	MemoryCache     *inmemory.Cache
+
+	// Learned is set when a chain or ensemble uses it, the statistics it reads need refreshing
+	Learned *learned.Provider
+
+	Age         *chain.Age
+	Gender      personClient.GenderProvider
+	Nationality *chain.Nationality
+}
+
+// MustProviders wires provider clients, caches and chains from config and panics on failure
+func MustProviders(log *slog.Logger, cfg *config.Config, storage *postgres.Storage) *Providers {
+	var p Providers
+
+	p.AgeClient = agify.New(log, cfg.Providers.Agify, storage)
+	p.GenderClient = genderize.New(log, cfg.Providers.Genderize, storage)
+	p.NationalityClient = nationalize.New(log, cfg.Providers.Nationalize, storage)
+
+	p.PredictionCache = cache.New(log,
+		storage,
+		cfg.Cache.TTL,
+		p.AgeClient,
+		p.GenderClient,
+		p.NationalityClient,
+	)
+
+	p.MemoryCache = inmemory.New(log,
+		cfg.Cache.MemorySize,
+		cfg.Cache.MemoryTTL,
+		p.PredictionCache,
+		p.PredictionCache,
+		p.PredictionCache,
+	)
+
+	var dataset *offline.Provider
+	if usesProvider(cfg.Providers, offlineProvider) {
+		var err error
+		if dataset, err = offline.Load(log, cfg.Providers.Offline.Path); err != nil {
+			panic(err)
+		}
+	}
+
+	if usesProvider(cfg.Providers, learnedProvider) {
+		p.Learned = learned.New(log, storage, cfg.Providers.Learned.MinSamples)
+	}
+
+	overrides := override.New(storage, cfg.Providers.Override.MinCorrections)
+
+	thresholds := chain.Thresholds{
+		MinProbability: cfg.Providers.Chain.MinProbability,
+		MinCount:       cfg.Providers.Chain.MinCount,
+	}
+
+	ensembles := cfg.Providers.Ensemble
+
+	ages := map[string]personClient.AgeProvider{
+		"agify":          p.MemoryCache,
+		overrideProvider: overrides,
+		offlineProvider:  dataset,
+		learnedProvider:  p.Learned,
+	}
+	if len(ensembles.Age) > 0 {
+		ages[ensembleProvider] = ensemble.NewAge(log, mustMembers("age", ensembles.Age, ages)...)
+	}
+
+	p.Age = chain.NewAge(log, thresholds, mustLinks("age", cfg.Providers.Chain.Age, ages)...)
+
+	nameRules := rules.New()
+
+	genders := map[string]personClient.GenderProvider{
+		"genderize":      p.MemoryCache,
+		overrideProvider: overrides,
+		offlineProvider:  dataset,
+		learnedProvider:  p.Learned,
+		rulesProvider:    nameRules,
+	}
+	if len(ensembles.Gender) > 0 {
+		genders[ensembleProvider] = ensemble.NewGender(log, mustMembers("gender", ensembles.Gender, genders)...)
+	}
+
+	p.Gender = chain.NewGender(log, thresholds, mustLinks("gender", cfg.Providers.Chain.Gender, genders)...)
+
+	if cfg.Providers.Rules.Check {
+		p.Gender = rules.NewCheck(log, p.Gender, nameRules)
+	}
+
+	nationalities := map[string]personClient.NationalityProvider{
+		"nationalize":    p.MemoryCache,
+		overrideProvider: overrides,
+		offlineProvider:  dataset,
+		learnedProvider:  p.Learned,
+	}
+	if len(ensembles.Nationality) > 0 {
+		nationalities[ensembleProvider] = ensemble.NewNationality(log,
+			mustMembers("nationality", ensembles.Nationality, nationalities)...)
+	}
+
+	p.Nationality = chain.NewNationality(log, thresholds,
+		mustLinks("nationality", cfg.Providers.Chain.Nationality, nationalities)...)
+
+	return &p
+}
+
+func PostgresURL(cfg *config.Config) string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		cfg.DB.User, cfg.DB.Password, cfg.DB.Host, cfg.DB.Port, cfg.DB.Name)
+}
+
+// mustLinks resolves configured provider names into chain links. Overrides are operator
+// answers and rules have no sample count, both are trusted regardless of thresholds.
+func mustLinks[P any](kind string, names []string, providers map[string]P) []chain.Link[P] {
+	links := make([]chain.Link[P], 0, len(names))
+	for _, name := range names {
+		provider, ok := providers[name]
+		if !ok {
+			panic(fmt.Sprintf("unknown %s provider: %s", kind, name))
+		}
+
+		links = append(links, chain.Link[P]{
+			Name:     name,
+			Provider: provider,
+			Trusted:  name == overrideProvider || name == rulesProvider,
+		})
+	}
+
+	return links
+}
+
+// mustMembers resolves configured weights into ensemble members ordered by name
+func mustMembers[P any](kind string, weights map[string]float64, providers map[string]P) []ensemble.Member[P] {
+	members := make([]ensemble.Member[P], 0, len(weights))
+	for _, name := range slices.Sorted(maps.Keys(weights)) {
+		provider, ok := providers[name]
+		if !ok {
+			panic(fmt.Sprintf("unknown %s ensemble member: %s", kind, name))
+		}
+
+		if weights[name] <= 0 {
+			panic(fmt.Sprintf("%s ensemble member %s must have a positive weight", kind, name))
+		}
+
+		members = append(members, ensemble.Member[P]{
+			Name:     name,
+			Weight:   weights[name],
+			Provider: provider,
+		})
+	}
+
+	return members
+}
+
+func usesProvider(cfg config.ProvidersConfig, name string) bool {
+	return slices.Contains(cfg.Chain.Age, name) ||
+		slices.Contains(cfg.Chain.Gender, name) ||
+		slices.Contains(cfg.Chain.Nationality, name) ||
+		cfg.Ensemble.Age[name] > 0 ||
+		cfg.Ensemble.Gender[name] > 0 ||
+		cfg.Ensemble.Nationality[name] > 0
+}
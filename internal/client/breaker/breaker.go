@@ -0,0 +1,136 @@
+package breaker
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"person-info/internal/config"
+)
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Breaker opens after a run of consecutive failures and rejects calls until
+// the open timeout passes, then lets a limited number of probes through
+type Breaker struct {
+	log              *slog.Logger
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenRequests int
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	probes   int
+	openedAt time.Time
+}
+
+func New(log *slog.Logger, name string, cfg config.BreakerConfig) *Breaker {
+	return &Breaker{
+		log:              log.With(slog.String("breaker", name)),
+		failureThreshold: cfg.Failures,
+		openTimeout:      cfg.OpenTimeout,
+		halfOpenRequests: max(cfg.HalfOpenRequests, 1),
+	}
+}
+
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		if time.Since(b.openedAt) < b.openTimeout {
+			return false
+		}
+
+		b.setState(StateHalfOpen)
+	}
+
+	if b.state == StateHalfOpen {
+		if b.probes >= b.halfOpenRequests {
+			return false
+		}
+
+		b.probes++
+	}
+
+	return true
+}
+
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+
+	if b.state == StateHalfOpen {
+		b.setState(StateClosed)
+	}
+}
+
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+
+	if b.state == StateHalfOpen || (b.failureThreshold > 0 && b.failures >= b.failureThreshold) {
+		b.openedAt = time.Now()
+		b.setState(StateOpen)
+	}
+}
+
+// Release returns an allowed call that finished without a verdict, e.g. canceled by the caller
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.openTimeout {
+		return StateHalfOpen
+	}
+
+	return b.state
+}
+
+func (b *Breaker) setState(state State) {
+	if b.state == state {
+		return
+	}
+
+	b.log.Warn("circuit breaker state changed",
+		slog.String("from", b.state.String()),
+		slog.String("to", state.String()),
+		slog.Int("failures", b.failures),
+	)
+
+	b.state = state
+	b.probes = 0
+}
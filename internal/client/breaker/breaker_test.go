@@ -0,0 +1,87 @@
+package breaker
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"person-info/internal/config"
+)
+
+func newBreaker(openTimeout time.Duration) *Breaker {
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)), "agify", config.BreakerConfig{
+		Failures:         3,
+		OpenTimeout:      openTimeout,
+		HalfOpenRequests: 1,
+	})
+}
+
+func TestOpensAfterConsecutiveFailures(t *testing.T) {
+	b := newBreaker(time.Hour)
+
+	b.Failure()
+	b.Failure()
+	b.Success()
+	b.Failure()
+	b.Failure()
+	assert.Equal(t, StateClosed, b.State())
+	assert.True(t, b.Allow())
+
+	b.Failure()
+	assert.Equal(t, StateOpen, b.State())
+	assert.False(t, b.Allow())
+}
+
+func TestHalfOpenProbe(t *testing.T) {
+	tests := []struct {
+		name    string
+		verdict func(b *Breaker)
+		want    State
+	}{
+		{name: "success closes", verdict: (*Breaker).Success, want: StateClosed},
+		{name: "failure opens again", verdict: (*Breaker).Failure, want: StateOpen},
+		{name: "release lets another probe through", verdict: (*Breaker).Release, want: StateHalfOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(10 * time.Millisecond)
+			for range 3 {
+				b.Failure()
+			}
+			require.False(t, b.Allow())
+
+			time.Sleep(20 * time.Millisecond)
+			require.Equal(t, StateHalfOpen, b.State())
+
+			require.True(t, b.Allow())
+			assert.False(t, b.Allow(), "only one probe is allowed")
+
+			tt.verdict(b)
+
+			if tt.want == StateOpen {
+				// the open timeout starts over
+				assert.False(t, b.Allow())
+				return
+			}
+
+			assert.Equal(t, tt.want, b.State())
+			assert.True(t, b.Allow())
+		})
+	}
+}
+
+func TestDisabledThreshold(t *testing.T) {
+	b := New(slog.New(slog.NewTextHandler(io.Discard, nil)), "agify", config.BreakerConfig{})
+
+	for range 100 {
+		b.Failure()
+	}
+
+	assert.Equal(t, StateClosed, b.State())
+	assert.True(t, b.Allow())
+}
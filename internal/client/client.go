@@ -1,7 +1,234 @@
 package client
 
-import "time"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
 
-var (
-	APIRequestTimeout = 5 * time.Second
+	"github.com/go-resty/resty/v2"
+
+	"person-info/internal/client/breaker"
+	personClient "person-info/internal/client/person"
+	"person-info/internal/client/quota"
+	"person-info/internal/config"
+	"person-info/internal/domain/model"
+)
+
+const (
+	// MaxBatchSize is the largest number of names the prediction APIs accept per request
+	MaxBatchSize = 10
 )
+
+// Client is a resty based client shared by the prediction APIs.
+// It retries transient failures, stops calling the API before its daily quota is exhausted
+// and fails fast while the API keeps failing.
+type Client struct {
+	client  *resty.Client
+	breaker *breaker.Breaker
+	quota   *quota.Quota
+	baseURL string
+	apiKey  string
+	timeout time.Duration
+	enabled bool
+
+	mu        sync.Mutex
+	remaining int
+	resetAt   time.Time
+}
+
+func New(
+	log *slog.Logger,
+	name string,
+	cfg config.ProviderConfig,
+	defaultBaseURL string,
+	usage quota.Storage,
+) *Client {
+	baseURL := cfg.URL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	client := resty.New().
+		SetRetryCount(cfg.RetryCount).
+		SetRetryWaitTime(cfg.RetryWait).
+		SetRetryMaxWaitTime(cfg.RetryMaxWait).
+		AddRetryCondition(retryable)
+
+	return &Client{
+		client:    client,
+		breaker:   breaker.New(log, name, cfg.Breaker),
+		quota:     quota.New(log, usage, name, cfg.Quota),
+		baseURL:   baseURL,
+		apiKey:    cfg.APIKey,
+		timeout:   cfg.Timeout,
+		enabled:   cfg.Enabled,
+		remaining: -1,
+	}
+}
+
+func (c *Client) Get(ctx context.Context, params url.Values, result any) (*resty.Response, error) {
+	if !c.enabled {
+		return nil, personClient.ErrProviderDisabled
+	}
+
+	if err := c.checkRateLimit(); err != nil {
+		return nil, err
+	}
+
+	names := len(params["name"]) + len(params["name[]"])
+
+	if err := c.quota.Allow(ctx, names); err != nil {
+		return nil, err
+	}
+
+	if !c.breaker.Allow() {
+		c.quota.Release(names)
+		return nil, personClient.ErrCircuitOpen
+	}
+
+	resp, err := c.get(ctx, params, result)
+	if resp != nil && resp.RawResponse != nil {
+		c.quota.Record(ctx, names, resp.RawResponse)
+	} else {
+		c.quota.Release(names)
+	}
+
+	switch {
+	case err == nil, errors.Is(err, personClient.ErrRateLimited), errors.Is(err, personClient.ErrInvalidName):
+		c.breaker.Success()
+	case errors.Is(err, context.Canceled):
+		c.breaker.Release()
+	default:
+		c.breaker.Failure()
+	}
+
+	return resp, err
+}
+
+// BatchParams builds query params for a multi-name request
+func BatchParams(names []string) url.Values {
+	return url.Values{"name[]": names}
+}
+
+func (c *Client) CircuitState() string {
+	return c.breaker.State().String()
+}
+
+// Usage reports how much of today's quota was used
+func (c *Client) Usage(ctx context.Context) (*model.ProviderUsage, error) {
+	return c.quota.Usage(ctx)
+}
+
+// get performs the request and classifies failures into the provider errors
+func (c *Client) get(parent context.Context, params url.Values, result any) (*resty.Response, error) {
+	ctx, cancel := context.WithTimeout(parent, c.timeout)
+	defer cancel()
+
+	if c.apiKey != "" {
+		params.Set("apikey", c.apiKey)
+	}
+
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetQueryParamsFromValues(params).
+		SetResult(result).
+		Get(c.baseURL)
+	if err != nil {
+		switch {
+		case parent.Err() != nil:
+			return nil, parent.Err()
+		case ctx.Err() != nil:
+			return nil, fmt.Errorf("%w after %s", personClient.ErrTimeout, c.timeout)
+		}
+
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, fmt.Errorf("%w: %w", personClient.ErrTimeout, err)
+		}
+
+		return nil, fmt.Errorf("%w: %w", personClient.ErrUpstreamUnavailable, err)
+	}
+
+	c.updateRateLimit(resp)
+
+	switch code := resp.StatusCode(); {
+	case code == http.StatusTooManyRequests:
+		return resp, &personClient.RateLimitError{RetryAfter: c.retryAfter(resp)}
+	case code == http.StatusUnprocessableEntity:
+		return resp, fmt.Errorf("%w: %s", personClient.ErrInvalidName, resp.String())
+	case code == http.StatusGatewayTimeout:
+		return resp, fmt.Errorf("%w: upstream status %s", personClient.ErrTimeout, resp.Status())
+	case resp.IsError():
+		return resp, fmt.Errorf("%w: unexpected response status %s", personClient.ErrUpstreamUnavailable, resp.Status())
+	}
+
+	return resp, nil
+}
+
+func (c *Client) checkRateLimit() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.remaining != 0 {
+		return nil
+	}
+
+	if wait := time.Until(c.resetAt); wait > 0 {
+		return &personClient.RateLimitError{RetryAfter: wait}
+	}
+
+	c.remaining = -1
+
+	return nil
+}
+
+func (c *Client) updateRateLimit(resp *resty.Response) {
+	remaining, err := strconv.Atoi(resp.Header().Get(quota.RemainingHeader))
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remaining = remaining
+
+	if reset, err := strconv.Atoi(resp.Header().Get(quota.ResetHeader)); err == nil {
+		c.resetAt = time.Now().Add(time.Duration(reset) * time.Second)
+	}
+}
+
+func (c *Client) retryAfter(resp *resty.Response) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header().Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return max(time.Until(c.resetAt), 0)
+}
+
+// retryable reports whether a failed attempt is worth repeating:
+// network errors, 5xx and 429 responses unless the quota is exhausted
+func retryable(resp *resty.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch code := resp.StatusCode(); {
+	case code == http.StatusTooManyRequests:
+		return resp.Header().Get(quota.RemainingHeader) != "0"
+	case code >= http.StatusInternalServerError:
+		return true
+	default:
+		return false
+	}
+}
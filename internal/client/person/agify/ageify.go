@@ -4,26 +4,29 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-
-	"github.com/go-resty/resty/v2"
+	"net/url"
+	"slices"
 
 	httpClient "person-info/internal/client"
 	personClient "person-info/internal/client/person"
+	"person-info/internal/client/quota"
+	"person-info/internal/config"
+	"person-info/internal/domain/model"
 )
 
 const (
-	agifyBaseURL = "https://api.agify.io"
+	defaultAgifyBaseURL = "https://api.agify.io"
 )
 
 type Client struct {
 	log    *slog.Logger
-	client *resty.Client
+	client *httpClient.Client
 }
 
-func New(log *slog.Logger) *Client {
+func New(log *slog.Logger, cfg config.ProviderConfig, usage quota.Storage) *Client {
 	return &Client{
 		log:    log,
-		client: resty.New(),
+		client: httpClient.New(log, "agify", cfg, defaultAgifyBaseURL, usage),
 	}
 }
 
@@ -33,37 +36,93 @@ type Response struct {
 	Count int    `json:"count"`
 }
 
-func (c *Client) Age(ctx context.Context, name string) (int, error) {
+func (c *Client) CircuitState() string {
+	return c.client.CircuitState()
+}
+
+func (c *Client) Usage(ctx context.Context) (*model.ProviderUsage, error) {
+	return c.client.Usage(ctx)
+}
+
+func (c *Client) Age(ctx context.Context, query model.NameQuery) (model.AgePrediction, error) {
 	const op = "client.person.agify.Age"
 
 	log := c.log.With(
 		slog.String("op", op),
-		slog.String("name", name),
+		slog.String("name", query.Name),
 	)
 
 	log.Info("predicting person age")
 
-	ctx, cancel := context.WithTimeout(ctx, httpClient.APIRequestTimeout)
-	defer cancel()
+	params := url.Values{"name": {query.Name}}
+	if query.CountryID != "" {
+		params.Set("country_id", query.CountryID)
+	}
 
 	var result Response
-	resp, err := c.client.R().
-		SetContext(ctx).
-		SetQueryParam("name", name).
-		SetResult(&result).
-		Get(agifyBaseURL)
+	resp, err := c.client.Get(ctx, params, &result)
 	if err != nil {
-		if ctx.Err() != nil {
-			return 0, fmt.Errorf("%s: %w", op, ctx.Err())
-		}
-		return 0, fmt.Errorf("%s: %w", op, err)
+		return model.AgePrediction{}, fmt.Errorf("%s: %w", op, err)
 	}
 
 	log.Debug("api.agify response status", slog.String("status", resp.Status()))
 
 	if result.Age == 0 {
-		return 0, fmt.Errorf("%s: %w", op, personClient.ErrInvalidName)
+		return model.AgePrediction{}, fmt.Errorf("%s: %w", op, personClient.ErrInvalidName)
+	}
+
+	return result.prediction(), nil
+}
+
+// Ages predicts ages for several names, names without a prediction get an empty result
+func (c *Client) Ages(ctx context.Context, queries []model.NameQuery) ([]model.AgePrediction, error) {
+	const op = "client.person.agify.Ages"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int("names", len(queries)),
+	)
+
+	log.Info("predicting people ages")
+
+	predictions := make([]model.AgePrediction, len(queries))
+	for countryID, indexes := range personClient.GroupByCountry(queries) {
+		for chunk := range slices.Chunk(indexes, httpClient.MaxBatchSize) {
+			names := make([]string, len(chunk))
+			for i, idx := range chunk {
+				names[i] = queries[idx].Name
+			}
+
+			params := httpClient.BatchParams(names)
+			if countryID != "" {
+				params.Set("country_id", countryID)
+			}
+
+			var result []Response
+			resp, err := c.client.Get(ctx, params, &result)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+
+			log.Debug("api.agify response status", slog.String("status", resp.Status()))
+
+			if len(result) != len(chunk) {
+				return nil, fmt.Errorf("%s: %w: got %d predictions for %d names",
+					op, personClient.ErrUpstreamUnavailable, len(result), len(chunk))
+			}
+
+			for i, r := range result {
+				predictions[chunk[i]] = r.prediction()
+			}
+		}
 	}
 
-	return result.Age, nil
+	return predictions, nil
+}
+
+func (r Response) prediction() model.AgePrediction {
+	return model.AgePrediction{
+		Age:   r.Age,
+		Count: r.Count,
+	}
 }
@@ -0,0 +1,312 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+	"person-info/internal/lib/logger/sl"
+	"person-info/internal/storage"
+)
+
+const (
+	KindAge         = "age"
+	KindGender      = "gender"
+	KindNationality = "nationality"
+)
+
+type Storage interface {
+	Prediction(ctx context.Context, name, countryID, kind string, ttl time.Duration) ([]byte, error)
+	Predictions(
+		ctx context.Context,
+		names []string,
+		countryID, kind string,
+		ttl time.Duration,
+	) (map[string][]byte, error)
+	SavePrediction(ctx context.Context, name, countryID, kind string, payload []byte) error
+	DeletePredictions(ctx context.Context, name string) (int64, error)
+	PredictionsCount(ctx context.Context) (int64, error)
+}
+
+type counter struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// Cache is a persistent read-through cache in front of the prediction providers
+type Cache struct {
+	log                 *slog.Logger
+	storage             Storage
+	ttl                 time.Duration
+	ageProvider         personClient.AgeProvider
+	genderProvider      personClient.GenderProvider
+	nationalityProvider personClient.NationalityProvider
+	counters            map[string]*counter
+}
+
+func New(
+	log *slog.Logger,
+	storage Storage,
+	ttl time.Duration,
+	ageProvider personClient.AgeProvider,
+	genderProvider personClient.GenderProvider,
+	nationalityProvider personClient.NationalityProvider,
+) *Cache {
+	return &Cache{
+		log:                 log,
+		storage:             storage,
+		ttl:                 ttl,
+		ageProvider:         ageProvider,
+		genderProvider:      genderProvider,
+		nationalityProvider: nationalityProvider,
+		counters: map[string]*counter{
+			KindAge:         {},
+			KindGender:      {},
+			KindNationality: {},
+		},
+	}
+}
+
+type KindStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+type Stats struct {
+	Entries int64                `json:"entries"`
+	Kinds   map[string]KindStats `json:"kinds"`
+}
+
+func (c *Cache) Age(ctx context.Context, query model.NameQuery) (model.AgePrediction, error) {
+	return lookup(ctx, c, KindAge, query, c.ageProvider.Age)
+}
+
+func (c *Cache) Gender(ctx context.Context, query model.NameQuery) (model.GenderPrediction, error) {
+	return lookup(ctx, c, KindGender, query, c.genderProvider.Gender)
+}
+
+func (c *Cache) Nationality(ctx context.Context, query model.NameQuery) (model.NationalityPrediction, error) {
+	query.CountryID = ""
+
+	return lookup(ctx, c, KindNationality, query, c.nationalityProvider.Nationality)
+}
+
+func (c *Cache) Ages(ctx context.Context, queries []model.NameQuery) ([]model.AgePrediction, error) {
+	return lookupBatch(ctx, c, KindAge, queries,
+		func(ctx context.Context, queries []model.NameQuery) ([]model.AgePrediction, error) {
+			return personClient.Ages(ctx, c.ageProvider, queries)
+		})
+}
+
+func (c *Cache) Genders(ctx context.Context, queries []model.NameQuery) ([]model.GenderPrediction, error) {
+	return lookupBatch(ctx, c, KindGender, queries,
+		func(ctx context.Context, queries []model.NameQuery) ([]model.GenderPrediction, error) {
+			return personClient.Genders(ctx, c.genderProvider, queries)
+		})
+}
+
+func (c *Cache) Nationalities(
+	ctx context.Context,
+	queries []model.NameQuery,
+) ([]model.NationalityPrediction, error) {
+	unlocalized := make([]model.NameQuery, len(queries))
+	for i, q := range queries {
+		q.CountryID = ""
+		unlocalized[i] = q
+	}
+
+	return lookupBatch(ctx, c, KindNationality, unlocalized,
+		func(ctx context.Context, queries []model.NameQuery) ([]model.NationalityPrediction, error) {
+			return personClient.Nationalities(ctx, c.nationalityProvider, queries)
+		})
+}
+
+func (c *Cache) Stats(ctx context.Context) (*Stats, error) {
+	const op = "client.person.cache.Stats"
+
+	entries, err := c.storage.PredictionsCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats := &Stats{
+		Entries: entries,
+		Kinds:   make(map[string]KindStats, len(c.counters)),
+	}
+	for kind, cnt := range c.counters {
+		stats.Kinds[kind] = KindStats{
+			Hits:   cnt.hits.Load(),
+			Misses: cnt.misses.Load(),
+		}
+	}
+
+	return stats, nil
+}
+
+// Invalidate removes cached predictions for the name, or every entry if name is empty
+func (c *Cache) Invalidate(ctx context.Context, name string) (int64, error) {
+	const op = "client.person.cache.Invalidate"
+
+	n, err := c.storage.DeletePredictions(ctx, normalize(name))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.log.Info("cache invalidated",
+		slog.String("op", op),
+		slog.String("name", name),
+		slog.Int64("deleted", n),
+	)
+
+	return n, nil
+}
+
+func lookup[T any](
+	ctx context.Context,
+	c *Cache,
+	kind string,
+	query model.NameQuery,
+	fetch func(ctx context.Context, query model.NameQuery) (T, error),
+) (T, error) {
+	const op = "client.person.cache.lookup"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("kind", kind),
+		slog.String("name", query.Name),
+		slog.String("country_id", query.CountryID),
+	)
+
+	key := normalize(query.Name)
+	cnt := c.counters[kind]
+
+	payload, err := c.storage.Prediction(ctx, key, query.CountryID, kind, c.ttl)
+	switch {
+	case err == nil:
+		var value T
+		if err := json.Unmarshal(payload, &value); err != nil {
+			log.Warn("failed to decode cached prediction", sl.Err(err))
+			break
+		}
+
+		cnt.hits.Add(1)
+		log.Debug("cache hit")
+
+		return value, nil
+	case !errors.Is(err, storage.ErrPredictionNotFound):
+		log.Error("failed to read cached prediction", sl.Err(err))
+	}
+
+	cnt.misses.Add(1)
+	log.Debug("cache miss")
+
+	value, err := fetch(ctx, query)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	payload, err = json.Marshal(value)
+	if err != nil {
+		log.Error("failed to encode prediction", sl.Err(err))
+
+		return value, nil
+	}
+
+	if err := c.storage.SavePrediction(ctx, key, query.CountryID, kind, payload); err != nil {
+		log.Error("failed to save prediction", sl.Err(err))
+	}
+
+	return value, nil
+}
+
+func lookupBatch[T interface{ Empty() bool }](
+	ctx context.Context,
+	c *Cache,
+	kind string,
+	queries []model.NameQuery,
+	fetch func(ctx context.Context, queries []model.NameQuery) ([]T, error),
+) ([]T, error) {
+	const op = "client.person.cache.lookupBatch"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("kind", kind),
+		slog.Int("names", len(queries)),
+	)
+
+	cnt := c.counters[kind]
+	predictions := make([]T, len(queries))
+
+	var (
+		missed      []model.NameQuery
+		missedIndex []int
+	)
+	for countryID, indexes := range personClient.GroupByCountry(queries) {
+		keys := make([]string, len(indexes))
+		for i, idx := range indexes {
+			keys[i] = normalize(queries[idx].Name)
+		}
+
+		payloads, err := c.storage.Predictions(ctx, keys, countryID, kind, c.ttl)
+		if err != nil {
+			log.Error("failed to read cached predictions", sl.Err(err))
+		}
+
+		for i, idx := range indexes {
+			if payload, ok := payloads[keys[i]]; ok {
+				if err := json.Unmarshal(payload, &predictions[idx]); err == nil {
+					cnt.hits.Add(1)
+					continue
+				}
+			}
+
+			cnt.misses.Add(1)
+			missed = append(missed, queries[idx])
+			missedIndex = append(missedIndex, idx)
+		}
+	}
+
+	log.Debug("cache lookup", slog.Int("missed", len(missed)))
+
+	if len(missed) == 0 {
+		return predictions, nil
+	}
+
+	fetched, err := fetch(ctx, missed)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, prediction := range fetched {
+		predictions[missedIndex[j]] = prediction
+
+		if prediction.Empty() {
+			continue
+		}
+
+		payload, err := json.Marshal(prediction)
+		if err != nil {
+			log.Error("failed to encode prediction", sl.Err(err))
+			continue
+		}
+
+		q := missed[j]
+		if err := c.storage.SavePrediction(ctx, normalize(q.Name), q.CountryID, kind, payload); err != nil {
+			log.Error("failed to save prediction", sl.Err(err))
+		}
+	}
+
+	return predictions, nil
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,380 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+	"person-info/internal/lib/logger/sl"
+)
+
+// Thresholds mark an answer as low confidence, the chain then asks the next provider.
+// Zero values accept any answer.
+type Thresholds struct {
+	MinProbability float64
+	MinCount       int
+}
+
+// Link is a provider in the chain, answers of a trusted link skip the thresholds
+type Link[P any] struct {
+	Name     string
+	Provider P
+	Trusted  bool
+}
+
+// Age asks providers in order until one answers with enough confidence
+type Age struct {
+	log        *slog.Logger
+	thresholds Thresholds
+	links      []Link[personClient.AgeProvider]
+}
+
+func NewAge(log *slog.Logger, thresholds Thresholds, links ...Link[personClient.AgeProvider]) *Age {
+	return &Age{
+		log:        log,
+		thresholds: thresholds,
+		links:      links,
+	}
+}
+
+func (c *Age) Age(ctx context.Context, query model.NameQuery) (model.AgePrediction, error) {
+	const op = "client.person.chain.Age"
+
+	steps := make([]step[model.AgePrediction], len(c.links))
+	for i, l := range c.links {
+		steps[i] = step[model.AgePrediction]{name: l.Name, trusted: l.Trusted, predict: l.Provider.Age}
+	}
+
+	prediction, err := resolve(ctx, c.log.With(slog.String("op", op)), query, steps,
+		c.confident, setAgeSource,
+	)
+	if err != nil {
+		return model.AgePrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prediction, nil
+}
+
+func (c *Age) Ages(ctx context.Context, queries []model.NameQuery) ([]model.AgePrediction, error) {
+	const op = "client.person.chain.Ages"
+
+	steps := make([]batchStep[model.AgePrediction], len(c.links))
+	for i, l := range c.links {
+		steps[i] = batchStep[model.AgePrediction]{
+			name:    l.Name,
+			trusted: l.Trusted,
+			predict: func(ctx context.Context, queries []model.NameQuery) ([]model.AgePrediction, error) {
+				return personClient.Ages(ctx, l.Provider, queries)
+			},
+		}
+	}
+
+	predictions, err := resolveBatch(ctx, c.log.With(slog.String("op", op)), queries, steps,
+		c.confident, setAgeSource,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return predictions, nil
+}
+
+func (c *Age) confident(p model.AgePrediction) bool {
+	return p.Count >= c.thresholds.MinCount
+}
+
+// Gender asks providers in order until one answers with enough confidence
+type Gender struct {
+	log        *slog.Logger
+	thresholds Thresholds
+	links      []Link[personClient.GenderProvider]
+}
+
+func NewGender(log *slog.Logger, thresholds Thresholds, links ...Link[personClient.GenderProvider]) *Gender {
+	return &Gender{
+		log:        log,
+		thresholds: thresholds,
+		links:      links,
+	}
+}
+
+func (c *Gender) Gender(ctx context.Context, query model.NameQuery) (model.GenderPrediction, error) {
+	const op = "client.person.chain.Gender"
+
+	steps := make([]step[model.GenderPrediction], len(c.links))
+	for i, l := range c.links {
+		steps[i] = step[model.GenderPrediction]{name: l.Name, trusted: l.Trusted, predict: l.Provider.Gender}
+	}
+
+	prediction, err := resolve(ctx, c.log.With(slog.String("op", op)), query, steps,
+		c.confident, setGenderSource,
+	)
+	if err != nil {
+		return model.GenderPrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prediction, nil
+}
+
+func (c *Gender) Genders(ctx context.Context, queries []model.NameQuery) ([]model.GenderPrediction, error) {
+	const op = "client.person.chain.Genders"
+
+	steps := make([]batchStep[model.GenderPrediction], len(c.links))
+	for i, l := range c.links {
+		steps[i] = batchStep[model.GenderPrediction]{
+			name:    l.Name,
+			trusted: l.Trusted,
+			predict: func(ctx context.Context, queries []model.NameQuery) ([]model.GenderPrediction, error) {
+				return personClient.Genders(ctx, l.Provider, queries)
+			},
+		}
+	}
+
+	predictions, err := resolveBatch(ctx, c.log.With(slog.String("op", op)), queries, steps,
+		c.confident, setGenderSource,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return predictions, nil
+}
+
+func (c *Gender) confident(p model.GenderPrediction) bool {
+	return p.Probability >= c.thresholds.MinProbability && p.Count >= c.thresholds.MinCount
+}
+
+// Nationality asks providers in order until one answers with enough confidence
+type Nationality struct {
+	log        *slog.Logger
+	thresholds Thresholds
+	links      []Link[personClient.NationalityProvider]
+}
+
+func NewNationality(log *slog.Logger, thresholds Thresholds, links ...Link[personClient.NationalityProvider]) *Nationality {
+	return &Nationality{
+		log:        log,
+		thresholds: thresholds,
+		links:      links,
+	}
+}
+
+func (c *Nationality) Nationality(
+	ctx context.Context,
+	query model.NameQuery,
+) (model.NationalityPrediction, error) {
+	const op = "client.person.chain.Nationality"
+
+	steps := make([]step[model.NationalityPrediction], len(c.links))
+	for i, l := range c.links {
+		steps[i] = step[model.NationalityPrediction]{name: l.Name, trusted: l.Trusted, predict: l.Provider.Nationality}
+	}
+
+	prediction, err := resolve(ctx, c.log.With(slog.String("op", op)), query, steps,
+		c.confident, setNationalitySource,
+	)
+	if err != nil {
+		return model.NationalityPrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prediction, nil
+}
+
+func (c *Nationality) Nationalities(ctx context.Context, queries []model.NameQuery) ([]model.NationalityPrediction, error) {
+	const op = "client.person.chain.Nationalities"
+
+	steps := make([]batchStep[model.NationalityPrediction], len(c.links))
+	for i, l := range c.links {
+		steps[i] = batchStep[model.NationalityPrediction]{
+			name:    l.Name,
+			trusted: l.Trusted,
+			predict: func(ctx context.Context, queries []model.NameQuery) ([]model.NationalityPrediction, error) {
+				return personClient.Nationalities(ctx, l.Provider, queries)
+			},
+		}
+	}
+
+	predictions, err := resolveBatch(ctx, c.log.With(slog.String("op", op)), queries, steps,
+		c.confident, setNationalitySource,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return predictions, nil
+}
+
+func (c *Nationality) confident(p model.NationalityPrediction) bool {
+	return p.Top().Probability >= c.thresholds.MinProbability && p.Count >= c.thresholds.MinCount
+}
+
+// Source setters keep a source set by the provider itself, e.g. a checked answer corrected by rules
+func setAgeSource(p *model.AgePrediction, source string) {
+	if p.Source == "" {
+		p.Source = source
+	}
+}
+
+func setGenderSource(p *model.GenderPrediction, source string) {
+	if p.Source == "" {
+		p.Source = source
+	}
+}
+
+func setNationalitySource(p *model.NationalityPrediction, source string) {
+	if p.Source == "" {
+		p.Source = source
+	}
+}
+
+type step[T any] struct {
+	name    string
+	trusted bool
+	predict func(ctx context.Context, query model.NameQuery) (T, error)
+}
+
+// resolve returns the first confident answer. If every provider either failed or
+// was unsure, the first unsure answer wins, otherwise the first failure is returned.
+func resolve[T any](
+	ctx context.Context,
+	log *slog.Logger,
+	query model.NameQuery,
+	steps []step[T],
+	confident func(T) bool,
+	setSource func(*T, string),
+) (T, error) {
+	var (
+		fallback    T
+		hasFallback bool
+		failure     = personClient.ErrInvalidName
+	)
+
+	log = log.With(slog.String("name", query.Name))
+
+	for _, s := range steps {
+		prediction, err := s.predict(ctx, query)
+		if err != nil {
+			if ctx.Err() != nil {
+				var zero T
+				return zero, ctx.Err()
+			}
+
+			log.Warn("provider failed, falling back", slog.String("provider", s.name), sl.Err(err))
+
+			failure = firstFailure(failure, err)
+			continue
+		}
+
+		setSource(&prediction, s.name)
+
+		if s.trusted || confident(prediction) {
+			log.Debug("provider answered", slog.String("provider", s.name))
+
+			return prediction, nil
+		}
+
+		log.Info("low confidence answer, falling back", slog.String("provider", s.name))
+
+		if !hasFallback {
+			fallback, hasFallback = prediction, true
+		}
+	}
+
+	if hasFallback {
+		return fallback, nil
+	}
+
+	var zero T
+	return zero, failure
+}
+
+type batchStep[T any] struct {
+	name    string
+	trusted bool
+	predict func(ctx context.Context, queries []model.NameQuery) ([]T, error)
+}
+
+// resolveBatch applies the resolve rules to every query, each provider is
+// asked once for all queries still lacking a confident answer
+func resolveBatch[T interface{ Empty() bool }](
+	ctx context.Context,
+	log *slog.Logger,
+	queries []model.NameQuery,
+	steps []batchStep[T],
+	confident func(T) bool,
+	setSource func(*T, string),
+) ([]T, error) {
+	var (
+		predictions = make([]T, len(queries))
+		resolved    = make([]bool, len(queries))
+		failure     error
+	)
+
+	log = log.With(slog.Int("names", len(queries)))
+
+	for _, s := range steps {
+		var (
+			pending      []model.NameQuery
+			pendingIndex []int
+		)
+		for i, q := range queries {
+			if !resolved[i] {
+				pending = append(pending, q)
+				pendingIndex = append(pendingIndex, i)
+			}
+		}
+
+		if len(pending) == 0 {
+			break
+		}
+
+		answers, err := s.predict(ctx, pending)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			log.Warn("provider failed, falling back", slog.String("provider", s.name), sl.Err(err))
+
+			failure = firstFailure(failure, err)
+			continue
+		}
+
+		for j, answer := range answers {
+			if answer.Empty() {
+				continue
+			}
+
+			i := pendingIndex[j]
+			setSource(&answer, s.name)
+
+			if s.trusted || confident(answer) {
+				predictions[i], resolved[i] = answer, true
+			} else if predictions[i].Empty() {
+				predictions[i] = answer
+			}
+		}
+	}
+
+	// a failing provider only matters if it left some names without any answer
+	for i := range queries {
+		if predictions[i].Empty() && failure != nil {
+			return nil, failure
+		}
+	}
+
+	return predictions, nil
+}
+
+// firstFailure keeps the first real provider failure, an unknown name from a later
+// provider must not hide a rate limit or an outage that the caller can retry
+func firstFailure(current, err error) error {
+	if current == nil || errors.Is(current, personClient.ErrInvalidName) {
+		return err
+	}
+
+	return current
+}
@@ -0,0 +1,186 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func answer(p model.GenderPrediction, err error) func(context.Context, model.NameQuery) (model.GenderPrediction, error) {
+	return func(context.Context, model.NameQuery) (model.GenderPrediction, error) {
+		return p, err
+	}
+}
+
+func TestResolve(t *testing.T) {
+	var (
+		rateLimit = &personClient.RateLimitError{RetryAfter: time.Minute}
+		invalid   = fmt.Errorf("offline: %w", personClient.ErrInvalidName)
+		outage    = fmt.Errorf("genderize: %w", personClient.ErrUpstreamUnavailable)
+		sure      = model.GenderPrediction{Gender: "female", Probability: 0.98, Count: 500}
+		unsure    = model.GenderPrediction{Gender: "male", Probability: 0.55, Count: 3}
+	)
+
+	tests := []struct {
+		name       string
+		steps      []step[model.GenderPrediction]
+		want       model.GenderPrediction
+		wantErr    error
+		wantSource string
+	}{
+		{
+			name: "first confident answer wins",
+			steps: []step[model.GenderPrediction]{
+				{name: "genderize", predict: answer(sure, nil)},
+				{name: "offline", predict: answer(unsure, nil)},
+			},
+			want:       sure,
+			wantSource: "genderize",
+		},
+		{
+			name: "low confidence falls back to the next provider",
+			steps: []step[model.GenderPrediction]{
+				{name: "genderize", predict: answer(unsure, nil)},
+				{name: "offline", predict: answer(sure, nil)},
+			},
+			want:       sure,
+			wantSource: "offline",
+		},
+		{
+			name: "trusted answer skips thresholds",
+			steps: []step[model.GenderPrediction]{
+				{name: "override", trusted: true, predict: answer(unsure, nil)},
+				{name: "genderize", predict: answer(sure, nil)},
+			},
+			want:       unsure,
+			wantSource: "override",
+		},
+		{
+			name: "first unsure answer when nobody is confident",
+			steps: []step[model.GenderPrediction]{
+				{name: "genderize", predict: answer(model.GenderPrediction{}, outage)},
+				{name: "offline", predict: answer(unsure, nil)},
+			},
+			want:       unsure,
+			wantSource: "offline",
+		},
+		{
+			name: "unknown name everywhere",
+			steps: []step[model.GenderPrediction]{
+				{name: "override", predict: answer(model.GenderPrediction{}, invalid)},
+				{name: "offline", predict: answer(model.GenderPrediction{}, invalid)},
+			},
+			wantErr: personClient.ErrInvalidName,
+		},
+		{
+			name: "rate limit is not hidden by a later unknown name",
+			steps: []step[model.GenderPrediction]{
+				{name: "genderize", predict: answer(model.GenderPrediction{}, rateLimit)},
+				{name: "offline", predict: answer(model.GenderPrediction{}, invalid)},
+			},
+			wantErr: personClient.ErrRateLimited,
+		},
+		{
+			name: "outage after an unknown name is reported",
+			steps: []step[model.GenderPrediction]{
+				{name: "override", predict: answer(model.GenderPrediction{}, invalid)},
+				{name: "genderize", predict: answer(model.GenderPrediction{}, outage)},
+				{name: "offline", predict: answer(model.GenderPrediction{}, invalid)},
+			},
+			wantErr: personClient.ErrUpstreamUnavailable,
+		},
+		{
+			name: "first failure wins over later ones",
+			steps: []step[model.GenderPrediction]{
+				{name: "genderize", predict: answer(model.GenderPrediction{}, rateLimit)},
+				{name: "backup", predict: answer(model.GenderPrediction{}, outage)},
+			},
+			wantErr: personClient.ErrRateLimited,
+		},
+	}
+
+	chain := NewGender(discard, Thresholds{MinProbability: 0.8, MinCount: 10})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolve(context.Background(), discard, model.NameQuery{Name: "Anna"}, tt.steps,
+				chain.confident, setGenderSource,
+			)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				if errors.Is(tt.wantErr, personClient.ErrRateLimited) {
+					var rl *personClient.RateLimitError
+					require.ErrorAs(t, err, &rl)
+					assert.Equal(t, time.Minute, rl.RetryAfter)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.Gender, got.Gender)
+			assert.Equal(t, tt.wantSource, got.Source)
+		})
+	}
+}
+
+func TestResolveBatch(t *testing.T) {
+	var (
+		invalid = fmt.Errorf("offline: %w", personClient.ErrInvalidName)
+		outage  = fmt.Errorf("genderize: %w", personClient.ErrUpstreamUnavailable)
+		queries = []model.NameQuery{{Name: "Anna"}, {Name: "Zyx"}}
+	)
+
+	batch := func(answers []model.GenderPrediction, err error) func(context.Context, []model.NameQuery) ([]model.GenderPrediction, error) {
+		return func(context.Context, []model.NameQuery) ([]model.GenderPrediction, error) {
+			return answers, err
+		}
+	}
+
+	chain := NewGender(discard, Thresholds{MinProbability: 0.8})
+
+	t.Run("outage is reported when a name is left without an answer", func(t *testing.T) {
+		_, err := resolveBatch(context.Background(), discard, queries, []batchStep[model.GenderPrediction]{
+			{name: "genderize", predict: batch(nil, outage)},
+			{name: "offline", predict: batch(nil, invalid)},
+		}, chain.confident, setGenderSource)
+
+		require.ErrorIs(t, err, personClient.ErrUpstreamUnavailable)
+	})
+
+	t.Run("names nobody knows stay empty", func(t *testing.T) {
+		got, err := resolveBatch(context.Background(), discard, queries, []batchStep[model.GenderPrediction]{
+			{name: "offline", predict: batch([]model.GenderPrediction{{Gender: "female", Probability: 0.9}, {}}, nil)},
+		}, chain.confident, setGenderSource)
+
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, "female", got[0].Gender)
+		assert.Equal(t, "offline", got[0].Source)
+		assert.True(t, got[1].Empty())
+	})
+
+	t.Run("failure is ignored once every name is answered", func(t *testing.T) {
+		answers := []model.GenderPrediction{{Gender: "female", Probability: 0.9}, {Gender: "male", Probability: 0.9}}
+
+		got, err := resolveBatch(context.Background(), discard, queries, []batchStep[model.GenderPrediction]{
+			{name: "genderize", predict: batch(nil, outage)},
+			{name: "offline", predict: batch(answers, nil)},
+		}, chain.confident, setGenderSource)
+
+		require.NoError(t, err)
+		assert.Equal(t, "male", got[1].Gender)
+	})
+}
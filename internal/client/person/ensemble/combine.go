@@ -0,0 +1,182 @@
+package ensemble
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+
+	"person-info/internal/domain/model"
+)
+
+var opposite = map[string]string{
+	"male":   "female",
+	"female": "male",
+}
+
+// combineAge takes the weighted mean age, disagreement is the weighted standard deviation in years
+func combineAge(votes []vote[model.AgePrediction]) model.AgePrediction {
+	var (
+		total, sum float64
+		count      int
+	)
+	for _, v := range votes {
+		if v.err == nil {
+			total += v.weight
+			sum += v.weight * float64(v.prediction.Age)
+			// members often learned from the same samples, adding up counts would count them twice
+			count = max(count, v.prediction.Count)
+		}
+	}
+
+	mean := sum / total
+
+	var variance float64
+	for _, v := range votes {
+		if v.err == nil {
+			d := float64(v.prediction.Age) - mean
+			variance += v.weight * d * d
+		}
+	}
+
+	return model.AgePrediction{
+		Age:   int(math.Round(mean)),
+		Count: count,
+		Ensemble: explain(votes, total, math.Sqrt(variance/total), func(p model.AgePrediction) (string, float64) {
+			return strconv.Itoa(p.Age), 0
+		}),
+	}
+}
+
+// combineGender adds up weighted probabilities of both genders
+func combineGender(votes []vote[model.GenderPrediction]) model.GenderPrediction {
+	var (
+		total  float64
+		count  int
+		scores = make(map[string]float64)
+	)
+	for _, v := range votes {
+		if v.err != nil {
+			continue
+		}
+
+		total += v.weight
+		count = max(count, v.prediction.Count)
+
+		scores[v.prediction.Gender] += v.weight * v.prediction.Probability
+		if other, ok := opposite[v.prediction.Gender]; ok {
+			scores[other] += v.weight * (1 - v.prediction.Probability)
+		}
+	}
+
+	gender := winner(scores)
+
+	return model.GenderPrediction{
+		Gender:      gender,
+		Probability: scores[gender] / total,
+		Count:       count,
+		Ensemble: explain(votes, total, dissent(votes, total, func(p model.GenderPrediction) bool {
+			return p.Gender != gender
+		}), func(p model.GenderPrediction) (string, float64) {
+			return p.Gender, p.Probability
+		}),
+	}
+}
+
+// combineNationality adds up weighted probabilities of every predicted country
+func combineNationality(votes []vote[model.NationalityPrediction]) model.NationalityPrediction {
+	var (
+		total  float64
+		count  int
+		scores = make(map[string]float64)
+	)
+	for _, v := range votes {
+		if v.err != nil {
+			continue
+		}
+
+		total += v.weight
+		count = max(count, v.prediction.Count)
+
+		for _, c := range v.prediction.Countries {
+			scores[c.CountryID] += v.weight * c.Probability
+		}
+	}
+
+	countries := make([]model.CountryPrediction, 0, len(scores))
+	for id, score := range scores {
+		countries = append(countries, model.CountryPrediction{CountryID: id, Probability: score / total})
+	}
+	slices.SortFunc(countries, func(a, b model.CountryPrediction) int {
+		return cmp.Or(cmp.Compare(b.Probability, a.Probability), cmp.Compare(a.CountryID, b.CountryID))
+	})
+
+	prediction := model.NationalityPrediction{
+		Countries: countries,
+		Count:     count,
+	}
+
+	top := prediction.Top().CountryID
+	prediction.Ensemble = explain(votes, total, dissent(votes, total, func(p model.NationalityPrediction) bool {
+		return p.Top().CountryID != top
+	}), func(p model.NationalityPrediction) (string, float64) {
+		return p.Top().CountryID, p.Top().Probability
+	})
+
+	return prediction
+}
+
+// winner picks the highest score, ties go to the first key in order so results are stable
+func winner(scores map[string]float64) string {
+	var (
+		best      string
+		bestScore = -1.0
+	)
+	for _, key := range slices.Sorted(maps.Keys(scores)) {
+		if scores[key] > bestScore {
+			best, bestScore = key, scores[key]
+		}
+	}
+
+	return best
+}
+
+// dissent is the weight share of answering members that differ from the result
+func dissent[T any](votes []vote[T], total float64, differs func(T) bool) float64 {
+	var against float64
+	for _, v := range votes {
+		if v.err == nil && differs(v.prediction) {
+			against += v.weight
+		}
+	}
+
+	return against / total
+}
+
+func explain[T any](
+	votes []vote[T],
+	total, disagreement float64,
+	answer func(T) (string, float64),
+) *model.Ensemble {
+	members := make([]model.EnsembleMember, len(votes))
+	for i, v := range votes {
+		members[i] = model.EnsembleMember{
+			Provider: v.member,
+			Weight:   v.weight,
+		}
+
+		if v.err != nil {
+			members[i].Error = v.err.Error()
+			continue
+		}
+
+		members[i].Share = v.weight / total
+		members[i].Answer, members[i].Probability = answer(v.prediction)
+	}
+
+	return &model.Ensemble{
+		Members:      members,
+		Disagreement: disagreement,
+	}
+}
@@ -0,0 +1,115 @@
+package ensemble
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+)
+
+func TestCombineAge(t *testing.T) {
+	votes := []vote[model.AgePrediction]{
+		{member: "agify", weight: 3, prediction: model.AgePrediction{Age: 40, Count: 1000}},
+		{member: "offline", weight: 1, prediction: model.AgePrediction{Age: 20, Count: 800}},
+		{member: "learned", weight: 5, err: personClient.ErrInvalidName},
+	}
+
+	got := combineAge(votes)
+
+	assert.Equal(t, 35, got.Age)
+	assert.Equal(t, 1000, got.Count)
+	require.NotNil(t, got.Ensemble)
+	// weighted standard deviation of 40, 40, 40, 20
+	assert.InDelta(t, 8.66, got.Ensemble.Disagreement, 0.01)
+	assert.InDelta(t, 0.75, got.Ensemble.Members[0].Share, 1e-9)
+	assert.Equal(t, "invalid person name", got.Ensemble.Members[2].Error)
+}
+
+func TestCombineGender(t *testing.T) {
+	votes := []vote[model.GenderPrediction]{
+		{member: "genderize", weight: 2, prediction: model.GenderPrediction{Gender: "female", Probability: 0.9, Count: 500}},
+		{member: "offline", weight: 1, prediction: model.GenderPrediction{Gender: "male", Probability: 0.6, Count: 500}},
+	}
+
+	got := combineGender(votes)
+
+	assert.Equal(t, "female", got.Gender)
+	// (2*0.9 + 1*0.4) / 3
+	assert.InDelta(t, 2.2/3, got.Probability, 1e-9)
+	assert.Equal(t, 500, got.Count)
+	assert.InDelta(t, 1.0/3, got.Ensemble.Disagreement, 1e-9)
+}
+
+func TestCombineNationality(t *testing.T) {
+	votes := []vote[model.NationalityPrediction]{
+		{member: "nationalize", weight: 1, prediction: model.NationalityPrediction{
+			Countries: []model.CountryPrediction{{CountryID: "UA", Probability: 0.5}, {CountryID: "RU", Probability: 0.3}},
+			Count:     200,
+		}},
+		{member: "offline", weight: 1, prediction: model.NationalityPrediction{
+			Countries: []model.CountryPrediction{{CountryID: "RU", Probability: 0.8}},
+			Count:     50,
+		}},
+	}
+
+	got := combineNationality(votes)
+
+	require.Len(t, got.Countries, 2)
+	assert.Equal(t, "RU", got.Top().CountryID)
+	assert.InDelta(t, 0.55, got.Top().Probability, 1e-9)
+	assert.Equal(t, 200, got.Count)
+	assert.InDelta(t, 0.5, got.Ensemble.Disagreement, 1e-9)
+}
+
+func TestCombineErrors(t *testing.T) {
+	outage := fmt.Errorf("genderize: %w", personClient.ErrUpstreamUnavailable)
+
+	tests := []struct {
+		name    string
+		votes   []vote[model.GenderPrediction]
+		wantErr error
+	}{
+		{
+			name: "unknown name everywhere",
+			votes: []vote[model.GenderPrediction]{
+				{member: "genderize", weight: 1, err: personClient.ErrInvalidName},
+				{member: "offline", weight: 1, err: personClient.ErrInvalidName},
+			},
+			wantErr: personClient.ErrInvalidName,
+		},
+		{
+			name: "failure wins over an unknown name",
+			votes: []vote[model.GenderPrediction]{
+				{member: "genderize", weight: 1, err: outage},
+				{member: "offline", weight: 1, err: personClient.ErrInvalidName},
+			},
+			wantErr: personClient.ErrUpstreamUnavailable,
+		},
+		{
+			name: "any answer is enough",
+			votes: []vote[model.GenderPrediction]{
+				{member: "genderize", weight: 1, err: outage},
+				{member: "offline", weight: 1, prediction: model.GenderPrediction{Gender: "male", Probability: 0.7}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := combine(tt.votes, combineGender)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "male", got.Gender)
+			assert.InDelta(t, 0.7, got.Probability, 1e-9)
+		})
+	}
+}
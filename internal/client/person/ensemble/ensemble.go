@@ -0,0 +1,308 @@
+package ensemble
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+	"person-info/internal/lib/logger/sl"
+)
+
+// Member is a provider of the ensemble, its answers count in proportion to Weight
+type Member[P any] struct {
+	Name     string
+	Weight   float64
+	Provider P
+}
+
+// Age averages ages of all members weighted by their weights
+type Age struct {
+	log     *slog.Logger
+	members []Member[personClient.AgeProvider]
+}
+
+func NewAge(log *slog.Logger, members ...Member[personClient.AgeProvider]) *Age {
+	return &Age{
+		log:     log,
+		members: members,
+	}
+}
+
+func (e *Age) Age(ctx context.Context, query model.NameQuery) (model.AgePrediction, error) {
+	const op = "client.person.ensemble.Age"
+
+	voters := make([]voter[model.AgePrediction], len(e.members))
+	for i, m := range e.members {
+		voters[i] = voter[model.AgePrediction]{name: m.Name, weight: m.Weight, predict: m.Provider.Age}
+	}
+
+	prediction, err := combine(ask(ctx, e.log.With(slog.String("op", op)), query, voters), combineAge)
+	if err != nil {
+		return model.AgePrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prediction, nil
+}
+
+func (e *Age) Ages(ctx context.Context, queries []model.NameQuery) ([]model.AgePrediction, error) {
+	const op = "client.person.ensemble.Ages"
+
+	voters := make([]batchVoter[model.AgePrediction], len(e.members))
+	for i, m := range e.members {
+		voters[i] = batchVoter[model.AgePrediction]{
+			name:   m.Name,
+			weight: m.Weight,
+			predict: func(ctx context.Context, queries []model.NameQuery) ([]model.AgePrediction, error) {
+				return personClient.Ages(ctx, m.Provider, queries)
+			},
+		}
+	}
+
+	predictions, err := combineBatch(askBatch(ctx, e.log.With(slog.String("op", op)), queries, voters), combineAge)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return predictions, nil
+}
+
+// Gender is a weighted vote of the members, each voting with its probability
+type Gender struct {
+	log     *slog.Logger
+	members []Member[personClient.GenderProvider]
+}
+
+func NewGender(log *slog.Logger, members ...Member[personClient.GenderProvider]) *Gender {
+	return &Gender{
+		log:     log,
+		members: members,
+	}
+}
+
+func (e *Gender) Gender(ctx context.Context, query model.NameQuery) (model.GenderPrediction, error) {
+	const op = "client.person.ensemble.Gender"
+
+	voters := make([]voter[model.GenderPrediction], len(e.members))
+	for i, m := range e.members {
+		voters[i] = voter[model.GenderPrediction]{name: m.Name, weight: m.Weight, predict: m.Provider.Gender}
+	}
+
+	prediction, err := combine(ask(ctx, e.log.With(slog.String("op", op)), query, voters), combineGender)
+	if err != nil {
+		return model.GenderPrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prediction, nil
+}
+
+func (e *Gender) Genders(ctx context.Context, queries []model.NameQuery) ([]model.GenderPrediction, error) {
+	const op = "client.person.ensemble.Genders"
+
+	voters := make([]batchVoter[model.GenderPrediction], len(e.members))
+	for i, m := range e.members {
+		voters[i] = batchVoter[model.GenderPrediction]{
+			name:   m.Name,
+			weight: m.Weight,
+			predict: func(ctx context.Context, queries []model.NameQuery) ([]model.GenderPrediction, error) {
+				return personClient.Genders(ctx, m.Provider, queries)
+			},
+		}
+	}
+
+	predictions, err := combineBatch(askBatch(ctx, e.log.With(slog.String("op", op)), queries, voters), combineGender)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return predictions, nil
+}
+
+// Nationality is a weighted vote of the members over every country they predicted
+type Nationality struct {
+	log     *slog.Logger
+	members []Member[personClient.NationalityProvider]
+}
+
+func NewNationality(log *slog.Logger, members ...Member[personClient.NationalityProvider]) *Nationality {
+	return &Nationality{
+		log:     log,
+		members: members,
+	}
+}
+
+func (e *Nationality) Nationality(
+	ctx context.Context,
+	query model.NameQuery,
+) (model.NationalityPrediction, error) {
+	const op = "client.person.ensemble.Nationality"
+
+	voters := make([]voter[model.NationalityPrediction], len(e.members))
+	for i, m := range e.members {
+		voters[i] = voter[model.NationalityPrediction]{name: m.Name, weight: m.Weight, predict: m.Provider.Nationality}
+	}
+
+	prediction, err := combine(ask(ctx, e.log.With(slog.String("op", op)), query, voters), combineNationality)
+	if err != nil {
+		return model.NationalityPrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prediction, nil
+}
+
+func (e *Nationality) Nationalities(
+	ctx context.Context,
+	queries []model.NameQuery,
+) ([]model.NationalityPrediction, error) {
+	const op = "client.person.ensemble.Nationalities"
+
+	voters := make([]batchVoter[model.NationalityPrediction], len(e.members))
+	for i, m := range e.members {
+		voters[i] = batchVoter[model.NationalityPrediction]{
+			name:   m.Name,
+			weight: m.Weight,
+			predict: func(ctx context.Context, queries []model.NameQuery) ([]model.NationalityPrediction, error) {
+				return personClient.Nationalities(ctx, m.Provider, queries)
+			},
+		}
+	}
+
+	predictions, err := combineBatch(askBatch(ctx, e.log.With(slog.String("op", op)), queries, voters),
+		combineNationality)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return predictions, nil
+}
+
+type voter[T any] struct {
+	name    string
+	weight  float64
+	predict func(ctx context.Context, query model.NameQuery) (T, error)
+}
+
+type batchVoter[T any] struct {
+	name    string
+	weight  float64
+	predict func(ctx context.Context, queries []model.NameQuery) ([]T, error)
+}
+
+// vote is the answer of a single member, err is set if the member has none
+type vote[T any] struct {
+	member     string
+	weight     float64
+	prediction T
+	err        error
+}
+
+// ask queries all members at once, unlike the chain every member is needed for the answer
+func ask[T any](ctx context.Context, log *slog.Logger, query model.NameQuery, voters []voter[T]) []vote[T] {
+	votes := make([]vote[T], len(voters))
+
+	var wg sync.WaitGroup
+	for i, v := range voters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			prediction, err := v.predict(ctx, query)
+			if err != nil && !errors.Is(err, personClient.ErrInvalidName) {
+				log.Warn("ensemble member failed", slog.String("provider", v.name), sl.Err(err))
+			}
+
+			votes[i] = vote[T]{member: v.name, weight: v.weight, prediction: prediction, err: err}
+		}()
+	}
+	wg.Wait()
+
+	return votes
+}
+
+// askBatch queries all members at once and returns the votes for each query
+func askBatch[T interface{ Empty() bool }](
+	ctx context.Context,
+	log *slog.Logger,
+	queries []model.NameQuery,
+	voters []batchVoter[T],
+) [][]vote[T] {
+	answers := make([][]T, len(voters))
+	errs := make([]error, len(voters))
+
+	var wg sync.WaitGroup
+	for i, v := range voters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			answers[i], errs[i] = v.predict(ctx, queries)
+			if errs[i] != nil {
+				log.Warn("ensemble member failed", slog.String("provider", v.name), sl.Err(errs[i]))
+			}
+		}()
+	}
+	wg.Wait()
+
+	votes := make([][]vote[T], len(queries))
+	for i := range queries {
+		votes[i] = make([]vote[T], len(voters))
+		for j, v := range voters {
+			votes[i][j] = vote[T]{member: v.name, weight: v.weight, err: errs[j]}
+
+			switch {
+			case errs[j] != nil:
+			case answers[j][i].Empty():
+				votes[i][j].err = personClient.ErrInvalidName
+			default:
+				votes[i][j].prediction = answers[j][i]
+			}
+		}
+	}
+
+	return votes
+}
+
+// combine merges the votes of members that answered,
+// with no answer at all the error of a failed member wins over ErrInvalidName
+func combine[T any](votes []vote[T], merge func(votes []vote[T]) T) (T, error) {
+	var (
+		answered []vote[T]
+		err      = personClient.ErrInvalidName
+	)
+	for _, v := range votes {
+		switch {
+		case v.err == nil:
+			answered = append(answered, v)
+		case !errors.Is(v.err, personClient.ErrInvalidName):
+			err = v.err
+		}
+	}
+
+	if len(answered) == 0 {
+		var zero T
+		return zero, err
+	}
+
+	return merge(votes), nil
+}
+
+// combineBatch combines votes of each query, queries nobody could predict get an empty result
+func combineBatch[T any](votes [][]vote[T], merge func(votes []vote[T]) T) ([]T, error) {
+	predictions := make([]T, len(votes))
+	for i := range votes {
+		prediction, err := combine(votes[i], merge)
+		if err != nil {
+			if errors.Is(err, personClient.ErrInvalidName) {
+				continue
+			}
+			return nil, err
+		}
+
+		predictions[i] = prediction
+	}
+
+	return predictions, nil
+}
@@ -4,26 +4,29 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-
-	"github.com/go-resty/resty/v2"
+	"net/url"
+	"slices"
 
 	httpClient "person-info/internal/client"
 	personClient "person-info/internal/client/person"
+	"person-info/internal/client/quota"
+	"person-info/internal/config"
+	"person-info/internal/domain/model"
 )
 
 const (
-	genderizeBaseURL = "https://api.genderize.io"
+	defaultGenderizeBaseURL = "https://api.genderize.io"
 )
 
 type Client struct {
 	log    *slog.Logger
-	client *resty.Client
+	client *httpClient.Client
 }
 
-func New(log *slog.Logger) *Client {
+func New(log *slog.Logger, cfg config.ProviderConfig, usage quota.Storage) *Client {
 	return &Client{
 		log:    log,
-		client: resty.New(),
+		client: httpClient.New(log, "genderize", cfg, defaultGenderizeBaseURL, usage),
 	}
 }
 
@@ -34,37 +37,94 @@ type Response struct {
 	Probability float64 `json:"probability"`
 }
 
-func (c *Client) Gender(ctx context.Context, name string) (string, error) {
+func (c *Client) CircuitState() string {
+	return c.client.CircuitState()
+}
+
+func (c *Client) Usage(ctx context.Context) (*model.ProviderUsage, error) {
+	return c.client.Usage(ctx)
+}
+
+func (c *Client) Gender(ctx context.Context, query model.NameQuery) (model.GenderPrediction, error) {
 	const op = "client.person.genderize.Gender"
 
 	log := c.log.With(
 		slog.String("op", op),
-		slog.String("name", name),
+		slog.String("name", query.Name),
 	)
 
 	log.Info("predicting person gender")
 
-	ctx, cancel := context.WithTimeout(ctx, httpClient.APIRequestTimeout)
-	defer cancel()
+	params := url.Values{"name": {query.Name}}
+	if query.CountryID != "" {
+		params.Set("country_id", query.CountryID)
+	}
 
 	var result Response
-	resp, err := c.client.R().
-		SetContext(ctx).
-		SetQueryParam("name", name).
-		SetResult(&result).
-		Get(genderizeBaseURL)
+	resp, err := c.client.Get(ctx, params, &result)
 	if err != nil {
-		if ctx.Err() != nil {
-			return "", fmt.Errorf("%s: %w", op, ctx.Err())
-		}
-		return "", fmt.Errorf("%s: %w", op, err)
+		return model.GenderPrediction{}, fmt.Errorf("%s: %w", op, err)
 	}
 
 	log.Debug("api.genderize response status", slog.String("status", resp.Status()))
 
 	if result.Gender == "" {
-		return "", fmt.Errorf("%s: %w", op, personClient.ErrInvalidName)
+		return model.GenderPrediction{}, fmt.Errorf("%s: %w", op, personClient.ErrInvalidName)
+	}
+
+	return result.prediction(), nil
+}
+
+// Genders predicts genders for several names, names without a prediction get an empty result
+func (c *Client) Genders(ctx context.Context, queries []model.NameQuery) ([]model.GenderPrediction, error) {
+	const op = "client.person.genderize.Genders"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int("names", len(queries)),
+	)
+
+	log.Info("predicting people genders")
+
+	predictions := make([]model.GenderPrediction, len(queries))
+	for countryID, indexes := range personClient.GroupByCountry(queries) {
+		for chunk := range slices.Chunk(indexes, httpClient.MaxBatchSize) {
+			names := make([]string, len(chunk))
+			for i, idx := range chunk {
+				names[i] = queries[idx].Name
+			}
+
+			params := httpClient.BatchParams(names)
+			if countryID != "" {
+				params.Set("country_id", countryID)
+			}
+
+			var result []Response
+			resp, err := c.client.Get(ctx, params, &result)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+
+			log.Debug("api.genderize response status", slog.String("status", resp.Status()))
+
+			if len(result) != len(chunk) {
+				return nil, fmt.Errorf("%s: %w: got %d predictions for %d names",
+					op, personClient.ErrUpstreamUnavailable, len(result), len(chunk))
+			}
+
+			for i, r := range result {
+				predictions[chunk[i]] = r.prediction()
+			}
+		}
 	}
 
-	return result.Gender, nil
+	return predictions, nil
+}
+
+func (r Response) prediction() model.GenderPrediction {
+	return model.GenderPrediction{
+		Gender:      r.Gender,
+		Probability: r.Probability,
+		Count:       r.Count,
+	}
 }
@@ -0,0 +1,240 @@
+package inmemory
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+	"person-info/internal/lib/lru"
+)
+
+type key struct {
+	name      string
+	countryID string
+}
+
+func keyOf(query model.NameQuery) key {
+	return key{
+		name:      normalize(query.Name),
+		countryID: query.CountryID,
+	}
+}
+
+// Cache is a bounded in-process LRU in front of the prediction providers.
+// Concurrent lookups of the same name share a single upstream call.
+type Cache struct {
+	log                 *slog.Logger
+	ageProvider         personClient.AgeProvider
+	genderProvider      personClient.GenderProvider
+	nationalityProvider personClient.NationalityProvider
+	ages                *lru.Cache[key, model.AgePrediction]
+	genders             *lru.Cache[key, model.GenderPrediction]
+	nationalities       *lru.Cache[key, model.NationalityPrediction]
+	ageFlight           singleflight.Group
+	genderFlight        singleflight.Group
+	nationalityFlight   singleflight.Group
+}
+
+func New(
+	log *slog.Logger,
+	size int,
+	ttl time.Duration,
+	ageProvider personClient.AgeProvider,
+	genderProvider personClient.GenderProvider,
+	nationalityProvider personClient.NationalityProvider,
+) *Cache {
+	return &Cache{
+		log:                 log,
+		ageProvider:         ageProvider,
+		genderProvider:      genderProvider,
+		nationalityProvider: nationalityProvider,
+		ages:                lru.New[key, model.AgePrediction](size, ttl),
+		genders:             lru.New[key, model.GenderPrediction](size, ttl),
+		nationalities:       lru.New[key, model.NationalityPrediction](size, ttl),
+	}
+}
+
+func (c *Cache) Age(ctx context.Context, query model.NameQuery) (model.AgePrediction, error) {
+	return lookup(ctx, c.log, "age", query, c.ages, &c.ageFlight, c.ageProvider.Age)
+}
+
+func (c *Cache) Gender(ctx context.Context, query model.NameQuery) (model.GenderPrediction, error) {
+	return lookup(ctx, c.log, "gender", query, c.genders, &c.genderFlight, c.genderProvider.Gender)
+}
+
+func (c *Cache) Nationality(ctx context.Context, query model.NameQuery) (model.NationalityPrediction, error) {
+	query.CountryID = ""
+
+	return lookup(ctx, c.log, "nationality", query,
+		c.nationalities, &c.nationalityFlight, c.nationalityProvider.Nationality)
+}
+
+func (c *Cache) Ages(ctx context.Context, queries []model.NameQuery) ([]model.AgePrediction, error) {
+	return lookupBatch(ctx, queries, c.ages,
+		func(ctx context.Context, queries []model.NameQuery) ([]model.AgePrediction, error) {
+			return personClient.Ages(ctx, c.ageProvider, queries)
+		})
+}
+
+func (c *Cache) Genders(ctx context.Context, queries []model.NameQuery) ([]model.GenderPrediction, error) {
+	return lookupBatch(ctx, queries, c.genders,
+		func(ctx context.Context, queries []model.NameQuery) ([]model.GenderPrediction, error) {
+			return personClient.Genders(ctx, c.genderProvider, queries)
+		})
+}
+
+func (c *Cache) Nationalities(
+	ctx context.Context,
+	queries []model.NameQuery,
+) ([]model.NationalityPrediction, error) {
+	unlocalized := make([]model.NameQuery, len(queries))
+	for i, q := range queries {
+		q.CountryID = ""
+		unlocalized[i] = q
+	}
+
+	return lookupBatch(ctx, unlocalized, c.nationalities,
+		func(ctx context.Context, queries []model.NameQuery) ([]model.NationalityPrediction, error) {
+			return personClient.Nationalities(ctx, c.nationalityProvider, queries)
+		})
+}
+
+// Invalidate drops in-memory predictions for the name, or all of them if name is empty
+func (c *Cache) Invalidate(_ context.Context, name string) (int64, error) {
+	if name == "" {
+		n := int64(c.ages.Len() + c.genders.Len() + c.nationalities.Len())
+
+		c.ages.Purge()
+		c.genders.Purge()
+		c.nationalities.Purge()
+
+		return n, nil
+	}
+
+	name = normalize(name)
+	match := func(k key) bool { return k.name == name }
+
+	n := c.ages.DeleteFunc(match) + c.genders.DeleteFunc(match) + c.nationalities.DeleteFunc(match)
+
+	return int64(n), nil
+}
+
+func lookup[T any](
+	ctx context.Context,
+	log *slog.Logger,
+	kind string,
+	query model.NameQuery,
+	cache *lru.Cache[key, T],
+	flight *singleflight.Group,
+	fetch func(ctx context.Context, query model.NameQuery) (T, error),
+) (T, error) {
+	const op = "client.person.inmemory.lookup"
+
+	log = log.With(
+		slog.String("op", op),
+		slog.String("kind", kind),
+		slog.String("name", query.Name),
+		slog.String("country_id", query.CountryID),
+	)
+
+	k := keyOf(query)
+
+	if value, ok := cache.Get(k); ok {
+		log.Debug("in-memory cache hit")
+
+		return value, nil
+	}
+
+	// the shared call must outlive any single caller that gives up early
+	callCtx := context.WithoutCancel(ctx)
+
+	ch := flight.DoChan(k.name+"|"+k.countryID, func() (any, error) {
+		value, err := fetch(callCtx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		cache.Set(k, value)
+
+		return value, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			var zero T
+			return zero, res.Err
+		}
+
+		if res.Shared {
+			log.Debug("joined in-flight lookup")
+		}
+
+		return res.Val.(T), nil
+	}
+}
+
+func lookupBatch[T interface{ Empty() bool }](
+	ctx context.Context,
+	queries []model.NameQuery,
+	cache *lru.Cache[key, T],
+	fetch func(ctx context.Context, queries []model.NameQuery) ([]T, error),
+) ([]T, error) {
+	predictions := make([]T, len(queries))
+
+	var (
+		missed      []model.NameQuery
+		missedIndex [][]int
+		pending     = make(map[key]int)
+	)
+	for i, query := range queries {
+		k := keyOf(query)
+		if prediction, ok := cache.Get(k); ok {
+			predictions[i] = prediction
+			continue
+		}
+
+		// people sharing a name but not a surname are fetched once
+		if j, ok := pending[k]; ok {
+			missedIndex[j] = append(missedIndex[j], i)
+			continue
+		}
+
+		pending[k] = len(missed)
+		missed = append(missed, query)
+		missedIndex = append(missedIndex, []int{i})
+	}
+
+	if len(missed) == 0 {
+		return predictions, nil
+	}
+
+	fetched, err := fetch(ctx, missed)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, prediction := range fetched {
+		for _, i := range missedIndex[j] {
+			predictions[i] = prediction
+		}
+
+		if !prediction.Empty() {
+			cache.Set(keyOf(missed[j]), prediction)
+		}
+	}
+
+	return predictions, nil
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
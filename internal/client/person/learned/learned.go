@@ -0,0 +1,187 @@
+package learned
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+	"person-info/internal/lib/logger/sl"
+)
+
+type Storage interface {
+	NameStatistics(ctx context.Context, name, field string) (map[string]int, error)
+	RefreshNameStatistics(ctx context.Context) (int64, error)
+}
+
+// Provider predicts from value distributions of people already stored under the name.
+// Names with fewer than minSamples known values get ErrInvalidName, so the chain moves on.
+type Provider struct {
+	log        *slog.Logger
+	storage    Storage
+	minSamples int
+}
+
+func New(log *slog.Logger, storage Storage, minSamples int) *Provider {
+	return &Provider{
+		log:        log,
+		storage:    storage,
+		minSamples: max(minSamples, 1),
+	}
+}
+
+func (p *Provider) Age(ctx context.Context, query model.NameQuery) (model.AgePrediction, error) {
+	const op = "client.person.learned.Age"
+
+	counts, total, err := p.statistics(ctx, query, model.FieldAge)
+	if err != nil {
+		return model.AgePrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var sum int
+	for value, n := range counts {
+		age, err := strconv.Atoi(value)
+		if err != nil {
+			return model.AgePrediction{}, fmt.Errorf("%s: invalid age %q: %w", op, value, err)
+		}
+
+		sum += age * n
+	}
+
+	return model.AgePrediction{
+		Age:   int(math.Round(float64(sum) / float64(total))),
+		Count: total,
+	}, nil
+}
+
+func (p *Provider) Gender(ctx context.Context, query model.NameQuery) (model.GenderPrediction, error) {
+	const op = "client.person.learned.Gender"
+
+	counts, total, err := p.statistics(ctx, query, model.FieldGender)
+	if err != nil {
+		return model.GenderPrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	top := distribution(counts, total)[0]
+
+	return model.GenderPrediction{
+		Gender:      top.value,
+		Probability: top.probability,
+		Count:       total,
+	}, nil
+}
+
+func (p *Provider) Nationality(ctx context.Context, query model.NameQuery) (model.NationalityPrediction, error) {
+	const op = "client.person.learned.Nationality"
+
+	counts, total, err := p.statistics(ctx, query, model.FieldNationality)
+	if err != nil {
+		return model.NationalityPrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	shares := distribution(counts, total)
+
+	countries := make([]model.CountryPrediction, len(shares))
+	for i, s := range shares {
+		countries[i] = model.CountryPrediction{
+			CountryID:   s.value,
+			Probability: s.probability,
+		}
+	}
+
+	return model.NationalityPrediction{
+		Countries: countries,
+		Count:     total,
+	}, nil
+}
+
+// Start refreshes the statistics now and then every interval until ctx is done
+func (p *Provider) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			p.refresh(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (p *Provider) refresh(ctx context.Context) {
+	const op = "client.person.learned.refresh"
+
+	log := p.log.With(slog.String("op", op))
+
+	start := time.Now()
+
+	rows, err := p.storage.RefreshNameStatistics(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Error("failed to refresh name statistics", sl.Err(err))
+		}
+		return
+	}
+
+	log.Info("name statistics refreshed",
+		slog.Int64("rows", rows),
+		slog.Duration("took", time.Since(start)),
+	)
+}
+
+// statistics returns value counts of the field, names below the sample size are unknown
+func (p *Provider) statistics(ctx context.Context, query model.NameQuery, field string) (map[string]int, int, error) {
+	counts, err := p.storage.NameStatistics(ctx, normalize(query.Name), field)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	for _, n := range counts {
+		total += n
+	}
+
+	if total < p.minSamples {
+		return nil, 0, personClient.ErrInvalidName
+	}
+
+	return counts, total, nil
+}
+
+type share struct {
+	value       string
+	probability float64
+}
+
+// distribution turns counts into shares ordered from the most common value
+func distribution(counts map[string]int, total int) []share {
+	shares := make([]share, 0, len(counts))
+	for value, n := range counts {
+		shares = append(shares, share{
+			value:       value,
+			probability: float64(n) / float64(total),
+		})
+	}
+
+	slices.SortFunc(shares, func(a, b share) int {
+		return cmp.Or(cmp.Compare(b.probability, a.probability), cmp.Compare(a.value, b.value))
+	})
+
+	return shares
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -4,26 +4,30 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-
-	"github.com/go-resty/resty/v2"
+	"net/url"
+	"slices"
+	"sort"
 
 	httpClient "person-info/internal/client"
 	personClient "person-info/internal/client/person"
+	"person-info/internal/client/quota"
+	"person-info/internal/config"
+	"person-info/internal/domain/model"
 )
 
 const (
-	nationalizeBaseURL = "https://api.nationalize.io"
+	defaultNationalizeBaseURL = "https://api.nationalize.io"
 )
 
 type Client struct {
 	log    *slog.Logger
-	client *resty.Client
+	client *httpClient.Client
 }
 
-func New(log *slog.Logger) *Client {
+func New(log *slog.Logger, cfg config.ProviderConfig, usage quota.Storage) *Client {
 	return &Client{
 		log:    log,
-		client: resty.New(),
+		client: httpClient.New(log, "nationalize", cfg, defaultNationalizeBaseURL, usage),
 	}
 }
 
@@ -36,44 +40,93 @@ type Response struct {
 	} `json:"country"`
 }
 
-func (c *Client) Nationality(ctx context.Context, name string) (string, error) {
+func (c *Client) CircuitState() string {
+	return c.client.CircuitState()
+}
+
+func (c *Client) Usage(ctx context.Context) (*model.ProviderUsage, error) {
+	return c.client.Usage(ctx)
+}
+
+func (c *Client) Nationality(ctx context.Context, query model.NameQuery) (model.NationalityPrediction, error) {
 	const op = "client.person.nationalize.Nationality"
 
 	log := c.log.With(
 		slog.String("op", op),
-		slog.String("name", name),
+		slog.String("name", query.Name),
 	)
 
 	log.Info("predicting person nationality")
 
-	ctx, cancel := context.WithTimeout(ctx, httpClient.APIRequestTimeout)
-	defer cancel()
-
 	var result Response
-	resp, err := c.client.R().
-		SetContext(ctx).
-		SetQueryParam("name", name).
-		SetResult(&result).
-		Get(nationalizeBaseURL)
+	resp, err := c.client.Get(ctx, url.Values{"name": {query.Name}}, &result)
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return model.NationalityPrediction{}, fmt.Errorf("%s: %w", op, err)
 	}
 
 	log.Debug("api.nationalize response status", slog.String("status", resp.Status()))
 
 	if len(result.Country) == 0 {
-		return "", fmt.Errorf("%s: %s", op, personClient.ErrInvalidName)
+		return model.NationalityPrediction{}, fmt.Errorf("%s: %w", op, personClient.ErrInvalidName)
 	}
 
-	var nationality string
+	return result.prediction(), nil
+}
+
+// Nationalities predicts nationalities for several names, names without a prediction get an empty result
+func (c *Client) Nationalities(ctx context.Context, queries []model.NameQuery) ([]model.NationalityPrediction, error) {
+	const op = "client.person.nationalize.Nationalities"
 
-	maxProbability := 0.0
-	for _, country := range result.Country {
-		if country.Probability > maxProbability {
-			maxProbability = country.Probability
-			nationality = country.CountryID
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int("names", len(queries)),
+	)
+
+	log.Info("predicting people nationalities")
+
+	predictions := make([]model.NationalityPrediction, 0, len(queries))
+	for chunk := range slices.Chunk(queries, httpClient.MaxBatchSize) {
+		names := make([]string, len(chunk))
+		for i, q := range chunk {
+			names[i] = q.Name
+		}
+
+		var result []Response
+		resp, err := c.client.Get(ctx, httpClient.BatchParams(names), &result)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Debug("api.nationalize response status", slog.String("status", resp.Status()))
+
+		if len(result) != len(chunk) {
+			return nil, fmt.Errorf("%s: %w: got %d predictions for %d names",
+				op, personClient.ErrUpstreamUnavailable, len(result), len(chunk))
+		}
+
+		for _, r := range result {
+			predictions = append(predictions, r.prediction())
 		}
 	}
 
-	return nationality, nil
+	return predictions, nil
+}
+
+func (r Response) prediction() model.NationalityPrediction {
+	countries := make([]model.CountryPrediction, len(r.Country))
+	for i, country := range r.Country {
+		countries[i] = model.CountryPrediction{
+			CountryID:   country.CountryID,
+			Probability: country.Probability,
+		}
+	}
+
+	sort.SliceStable(countries, func(i, j int) bool {
+		return countries[i].Probability > countries[j].Probability
+	})
+
+	return model.NationalityPrediction{
+		Countries: countries,
+		Count:     r.Count,
+	}
 }
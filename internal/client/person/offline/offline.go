@@ -0,0 +1,200 @@
+package offline
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+)
+
+// Record is a dataset entry for a single name
+type Record struct {
+	Name              string    `json:"name"`
+	Age               int       `json:"age"`
+	Gender            string    `json:"gender"`
+	GenderProbability float64   `json:"gender_probability"`
+	Count             int       `json:"count"`
+	Countries         []Country `json:"countries"`
+}
+
+type Country struct {
+	CountryID   string  `json:"country_id"`
+	Probability float64 `json:"probability"`
+}
+
+// Provider answers age, gender and nationality predictions from a local dataset
+type Provider struct {
+	records map[string]*Record
+}
+
+// Load reads the dataset from a JSON array of records or a CSV file with
+// name,age,gender,gender_probability,count,country_id,country_probability columns,
+// one row per name and country
+func Load(log *slog.Logger, path string) (*Provider, error) {
+	const op = "client.person.offline.Load"
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer f.Close()
+
+	var records []*Record
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.NewDecoder(f).Decode(&records)
+	case ".csv":
+		records, err = readCSV(f)
+	default:
+		err = fmt.Errorf("unsupported dataset format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	p := &Provider{
+		records: make(map[string]*Record, len(records)),
+	}
+	for _, r := range records {
+		sort.SliceStable(r.Countries, func(i, j int) bool {
+			return r.Countries[i].Probability > r.Countries[j].Probability
+		})
+
+		p.records[normalize(r.Name)] = r
+	}
+
+	log.Info("offline dataset loaded",
+		slog.String("op", op),
+		slog.String("path", path),
+		slog.Int("names", len(p.records)),
+	)
+
+	return p, nil
+}
+
+func (p *Provider) Age(_ context.Context, query model.NameQuery) (model.AgePrediction, error) {
+	const op = "client.person.offline.Age"
+
+	r, ok := p.records[normalize(query.Name)]
+	if !ok || r.Age == 0 {
+		return model.AgePrediction{}, fmt.Errorf("%s: %w", op, personClient.ErrInvalidName)
+	}
+
+	return model.AgePrediction{
+		Age:   r.Age,
+		Count: r.Count,
+	}, nil
+}
+
+func (p *Provider) Gender(_ context.Context, query model.NameQuery) (model.GenderPrediction, error) {
+	const op = "client.person.offline.Gender"
+
+	r, ok := p.records[normalize(query.Name)]
+	if !ok || r.Gender == "" {
+		return model.GenderPrediction{}, fmt.Errorf("%s: %w", op, personClient.ErrInvalidName)
+	}
+
+	return model.GenderPrediction{
+		Gender:      r.Gender,
+		Probability: r.GenderProbability,
+		Count:       r.Count,
+	}, nil
+}
+
+func (p *Provider) Nationality(_ context.Context, query model.NameQuery) (model.NationalityPrediction, error) {
+	const op = "client.person.offline.Nationality"
+
+	r, ok := p.records[normalize(query.Name)]
+	if !ok || len(r.Countries) == 0 {
+		return model.NationalityPrediction{}, fmt.Errorf("%s: %w", op, personClient.ErrInvalidName)
+	}
+
+	countries := make([]model.CountryPrediction, len(r.Countries))
+	for i, c := range r.Countries {
+		countries[i] = model.CountryPrediction{
+			CountryID:   c.CountryID,
+			Probability: c.Probability,
+		}
+	}
+
+	return model.NationalityPrediction{
+		Countries: countries,
+		Count:     r.Count,
+	}, nil
+}
+
+func readCSV(r io.Reader) ([]*Record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 7
+
+	if _, err := reader.Read(); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	var (
+		records []*Record
+		byName  = make(map[string]*Record)
+	)
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		record, ok := byName[normalize(row[0])]
+		if !ok {
+			record = &Record{Name: row[0], Gender: row[2]}
+
+			if record.Age, err = strconv.Atoi(row[1]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid age: %w", line, err)
+			}
+
+			if record.GenderProbability, err = strconv.ParseFloat(row[3], 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid gender probability: %w", line, err)
+			}
+
+			if record.Count, err = strconv.Atoi(row[4]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid count: %w", line, err)
+			}
+
+			byName[normalize(row[0])] = record
+			records = append(records, record)
+		}
+
+		if row[5] == "" {
+			continue
+		}
+
+		probability, err := strconv.ParseFloat(row[6], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid country probability: %w", line, err)
+		}
+
+		record.Countries = append(record.Countries, Country{
+			CountryID:   row[5],
+			Probability: probability,
+		})
+	}
+
+	return records, nil
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,104 @@
+package offline
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func write(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestLoadCSV(t *testing.T) {
+	path := write(t, "names.csv", `name,age,gender,gender_probability,count,country_id,country_probability
+Alexey,39,male,1.00,28814,UA,0.2
+Alexey,39,male,1.00,28814,RU,0.55
+Sasha,30,female,0.55,900,,
+`)
+
+	p, err := Load(discard, path)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	age, err := p.Age(ctx, model.NameQuery{Name: " alexey "})
+	require.NoError(t, err)
+	assert.Equal(t, 39, age.Age)
+	assert.Equal(t, 28814, age.Count)
+
+	nationality, err := p.Nationality(ctx, model.NameQuery{Name: "Alexey"})
+	require.NoError(t, err)
+	require.Len(t, nationality.Countries, 2)
+	assert.Equal(t, "RU", nationality.Top().CountryID)
+
+	gender, err := p.Gender(ctx, model.NameQuery{Name: "Sasha"})
+	require.NoError(t, err)
+	assert.Equal(t, "female", gender.Gender)
+	assert.InDelta(t, 0.55, gender.Probability, 1e-9)
+
+	_, err = p.Nationality(ctx, model.NameQuery{Name: "Sasha"})
+	require.ErrorIs(t, err, personClient.ErrInvalidName)
+
+	_, err = p.Age(ctx, model.NameQuery{Name: "Zyx"})
+	require.ErrorIs(t, err, personClient.ErrInvalidName)
+}
+
+func TestLoadJSON(t *testing.T) {
+	path := write(t, "names.json", `[
+		{"name": "Anna", "age": 36, "gender": "female", "gender_probability": 0.98, "count": 5000,
+		 "countries": [{"country_id": "PL", "probability": 0.1}, {"country_id": "RU", "probability": 0.3}]}
+	]`)
+
+	p, err := Load(discard, path)
+	require.NoError(t, err)
+
+	nationality, err := p.Nationality(context.Background(), model.NameQuery{Name: "ANNA"})
+	require.NoError(t, err)
+	assert.Equal(t, "RU", nationality.Top().CountryID)
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "unsupported format", file: "names.txt", content: "Anna"},
+		{name: "invalid age", file: "names.csv", content: "header,,,,,,\nAnna,old,female,0.9,10,RU,0.5\n"},
+		{name: "wrong column count", file: "names.csv", content: "name,age\nAnna,30\n"},
+		{name: "malformed json", file: "names.json", content: "{"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(discard, write(t, tt.file, tt.content))
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadBundledDataset(t *testing.T) {
+	p, err := Load(discard, filepath.Join("..", "..", "..", "..", "data", "names.csv"))
+	require.NoError(t, err)
+
+	_, err = p.Gender(context.Background(), model.NameQuery{Name: "Alexander"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,102 @@
+package override
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+	"person-info/internal/storage"
+)
+
+type Storage interface {
+	NameOverride(ctx context.Context, name, field string) (*model.NameOverride, error)
+}
+
+// Provider answers with operator corrections stored per name, names without
+// an override get ErrInvalidName so the chain moves on to the next provider.
+// Overrides fed by edits of people count once minCorrections of them agree.
+type Provider struct {
+	storage        Storage
+	minCorrections int
+}
+
+func New(storage Storage, minCorrections int) *Provider {
+	return &Provider{
+		storage:        storage,
+		minCorrections: minCorrections,
+	}
+}
+
+func (p *Provider) Age(ctx context.Context, query model.NameQuery) (model.AgePrediction, error) {
+	const op = "client.person.override.Age"
+
+	o, err := p.override(ctx, query, model.FieldAge)
+	if err != nil {
+		return model.AgePrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	age, err := strconv.Atoi(o.Value)
+	if err != nil {
+		return model.AgePrediction{}, fmt.Errorf("%s: invalid age override: %w", op, err)
+	}
+
+	return model.AgePrediction{
+		Age:   age,
+		Count: o.Corrections,
+	}, nil
+}
+
+func (p *Provider) Gender(ctx context.Context, query model.NameQuery) (model.GenderPrediction, error) {
+	const op = "client.person.override.Gender"
+
+	o, err := p.override(ctx, query, model.FieldGender)
+	if err != nil {
+		return model.GenderPrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return model.GenderPrediction{
+		Gender:      o.Value,
+		Probability: 1,
+		Count:       o.Corrections,
+	}, nil
+}
+
+func (p *Provider) Nationality(ctx context.Context, query model.NameQuery) (model.NationalityPrediction, error) {
+	const op = "client.person.override.Nationality"
+
+	o, err := p.override(ctx, query, model.FieldNationality)
+	if err != nil {
+		return model.NationalityPrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return model.NationalityPrediction{
+		Countries: []model.CountryPrediction{{CountryID: o.Value, Probability: 1}},
+		Count:     o.Corrections,
+	}, nil
+}
+
+func (p *Provider) override(ctx context.Context, query model.NameQuery, field string) (*model.NameOverride, error) {
+	o, err := p.storage.NameOverride(ctx, Normalize(query.Name), field)
+	if err != nil {
+		if errors.Is(err, storage.ErrOverrideNotFound) {
+			return nil, personClient.ErrInvalidName
+		}
+
+		return nil, err
+	}
+
+	if !o.Pinned && o.Corrections < p.minCorrections {
+		return nil, personClient.ErrInvalidName
+	}
+
+	return o, nil
+}
+
+// Normalize is the form names are stored in overrides with
+func Normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
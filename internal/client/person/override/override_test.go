@@ -0,0 +1,61 @@
+package override
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+	"person-info/internal/storage"
+)
+
+type stubStorage map[string]*model.NameOverride
+
+func (s stubStorage) NameOverride(_ context.Context, name, field string) (*model.NameOverride, error) {
+	o, ok := s[name+"/"+field]
+	if !ok {
+		return nil, storage.ErrOverrideNotFound
+	}
+
+	return o, nil
+}
+
+func TestGender(t *testing.T) {
+	overrides := stubStorage{
+		"sasha/gender":  {Value: "female", Corrections: 3},
+		"zhenya/gender": {Value: "male", Corrections: 1},
+		"valya/gender":  {Value: "female", Pinned: true},
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		want        string
+		wantInvalid bool
+	}{
+		{name: "agreed corrections", query: " Sasha ", want: "female"},
+		{name: "single correction is not enough", query: "Zhenya", wantInvalid: true},
+		{name: "pinned override needs no corrections", query: "Valya", want: "female"},
+		{name: "no override", query: "Anna", wantInvalid: true},
+	}
+
+	p := New(overrides, 2)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Gender(context.Background(), model.NameQuery{Name: tt.query})
+
+			if tt.wantInvalid {
+				require.ErrorIs(t, err, personClient.ErrInvalidName)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Gender)
+			assert.Equal(t, 1.0, got.Probability)
+		})
+	}
+}
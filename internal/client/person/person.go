@@ -1,9 +1,143 @@
 package person
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"person-info/internal/domain/model"
+)
+
+const (
+	maxConcurrentLookups = 10
 )
 
+// Provider errors are classified by wrapping one of these
 var (
+	// ErrInvalidName means the provider can't predict anything for the name
 	ErrInvalidName = errors.New("invalid person name")
+	// ErrUpstreamUnavailable covers network failures, 5xx and malformed responses
+	ErrUpstreamUnavailable = errors.New("provider unavailable")
+	ErrRateLimited         = errors.New("provider rate limit exceeded")
+	ErrTimeout             = errors.New("provider timed out")
+
+	ErrProviderDisabled = fmt.Errorf("%w: provider disabled", ErrUpstreamUnavailable)
+	ErrCircuitOpen      = fmt.Errorf("%w: circuit breaker is open", ErrUpstreamUnavailable)
 )
+
+// RateLimitError reports that a provider refused the request because its quota is exhausted
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrRateLimited, e.RetryAfter)
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+type AgeProvider interface {
+	Age(ctx context.Context, query model.NameQuery) (model.AgePrediction, error)
+}
+
+type GenderProvider interface {
+	Gender(ctx context.Context, query model.NameQuery) (model.GenderPrediction, error)
+}
+
+type NationalityProvider interface {
+	Nationality(ctx context.Context, query model.NameQuery) (model.NationalityPrediction, error)
+}
+
+type AgeBatchProvider interface {
+	Ages(ctx context.Context, queries []model.NameQuery) ([]model.AgePrediction, error)
+}
+
+type GenderBatchProvider interface {
+	Genders(ctx context.Context, queries []model.NameQuery) ([]model.GenderPrediction, error)
+}
+
+type NationalityBatchProvider interface {
+	Nationalities(ctx context.Context, queries []model.NameQuery) ([]model.NationalityPrediction, error)
+}
+
+// Ages resolves queries with a single batch call when p supports it and one by one otherwise.
+// Queries without a prediction get an empty result.
+func Ages(ctx context.Context, p AgeProvider, queries []model.NameQuery) ([]model.AgePrediction, error) {
+	if batch, ok := p.(AgeBatchProvider); ok {
+		return batch.Ages(ctx, queries)
+	}
+
+	return each(ctx, queries, p.Age)
+}
+
+func Genders(
+	ctx context.Context,
+	p GenderProvider,
+	queries []model.NameQuery,
+) ([]model.GenderPrediction, error) {
+	if batch, ok := p.(GenderBatchProvider); ok {
+		return batch.Genders(ctx, queries)
+	}
+
+	return each(ctx, queries, p.Gender)
+}
+
+func Nationalities(
+	ctx context.Context,
+	p NationalityProvider,
+	queries []model.NameQuery,
+) ([]model.NationalityPrediction, error) {
+	if batch, ok := p.(NationalityBatchProvider); ok {
+		return batch.Nationalities(ctx, queries)
+	}
+
+	return each(ctx, queries, p.Nationality)
+}
+
+// GroupByCountry returns query indexes grouped by country, APIs localize a whole batch at once
+func GroupByCountry(queries []model.NameQuery) map[string][]int {
+	groups := make(map[string][]int)
+	for i, q := range queries {
+		groups[q.CountryID] = append(groups[q.CountryID], i)
+	}
+
+	return groups
+}
+
+func each[T any](
+	ctx context.Context,
+	queries []model.NameQuery,
+	fetch func(ctx context.Context, query model.NameQuery) (T, error),
+) ([]T, error) {
+	predictions := make([]T, len(queries))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentLookups)
+
+	for i, query := range queries {
+		g.Go(func() error {
+			prediction, err := fetch(ctx, query)
+			if err != nil {
+				if errors.Is(err, ErrInvalidName) {
+					return nil
+				}
+				return err
+			}
+
+			predictions[i] = prediction
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return predictions, nil
+}
@@ -0,0 +1,98 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+)
+
+// Check verifies gender answers of another provider against the rules, a contradicted
+// answer is replaced when the rules are more certain than the provider. Overrides are left as is.
+type Check struct {
+	log      *slog.Logger
+	provider personClient.GenderProvider
+	rules    *Provider
+}
+
+func NewCheck(log *slog.Logger, provider personClient.GenderProvider, rules *Provider) *Check {
+	return &Check{
+		log:      log,
+		provider: provider,
+		rules:    rules,
+	}
+}
+
+func (c *Check) Gender(ctx context.Context, query model.NameQuery) (model.GenderPrediction, error) {
+	const op = "client.person.rules.Check.Gender"
+
+	prediction, err := c.provider.Gender(ctx, query)
+	if err != nil {
+		// names the provider doesn't know can still be told by the rules
+		if errors.Is(err, personClient.ErrInvalidName) {
+			if checked, ok := c.rulesAnswer(ctx, query); ok {
+				return checked, nil
+			}
+		}
+
+		return model.GenderPrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return c.check(ctx, query, prediction), nil
+}
+
+func (c *Check) Genders(ctx context.Context, queries []model.NameQuery) ([]model.GenderPrediction, error) {
+	const op = "client.person.rules.Check.Genders"
+
+	predictions, err := personClient.Genders(ctx, c.provider, queries)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i, query := range queries {
+		if predictions[i].Empty() {
+			predictions[i], _ = c.rulesAnswer(ctx, query)
+			continue
+		}
+
+		predictions[i] = c.check(ctx, query, predictions[i])
+	}
+
+	return predictions, nil
+}
+
+func (c *Check) check(ctx context.Context, query model.NameQuery, prediction model.GenderPrediction) model.GenderPrediction {
+	if prediction.Source == model.SourceOverride {
+		return prediction
+	}
+
+	checked, ok := c.rulesAnswer(ctx, query)
+	if !ok || checked.Gender == prediction.Gender || checked.Probability <= prediction.Probability {
+		return prediction
+	}
+
+	c.log.Info("gender contradicts name rules",
+		slog.String("name", query.Name),
+		slog.String("surname", query.Surname),
+		slog.String("patronymic", query.Patronymic),
+		slog.String("predicted", prediction.Gender),
+		slog.String("source", prediction.Source),
+		slog.String("rules", checked.Gender),
+	)
+
+	return checked
+}
+
+func (c *Check) rulesAnswer(ctx context.Context, query model.NameQuery) (model.GenderPrediction, bool) {
+	prediction, err := c.rules.Gender(ctx, query)
+	if err != nil {
+		return model.GenderPrediction{}, false
+	}
+
+	prediction.Source = model.SourceRules
+
+	return prediction, true
+}
@@ -0,0 +1,128 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+)
+
+const (
+	male   = "male"
+	female = "female"
+
+	// both the patronymic and the surname point the same way
+	agreedProbability = 0.995
+)
+
+// namingCountries are Slavic and former Soviet countries, where patronymics and gendered
+// surname endings are common. Not all of them are Slavic. Other country hints disable the rules.
+var namingCountries = []string{"RU", "UA", "BY", "KZ", "KG", "UZ", "TJ", "MD", "AM", "AZ", "GE", "BG", "PL", "CZ", "SK", "RS", "LV", "LT", "EE"}
+
+type ending struct {
+	suffix      string
+	gender      string
+	probability float64
+}
+
+// Endings are matched longest first, latin ones are the common transliterations
+var (
+	patronymicEndings = sortEndings([]ending{
+		{"ovich", male, 0.99}, {"evich", male, 0.99}, {"ich", male, 0.97}, {"ogly", male, 0.99}, {"oglu", male, 0.99},
+		{"ovna", female, 0.99}, {"evna", female, 0.99}, {"ichna", female, 0.99}, {"kyzy", female, 0.99}, {"qizi", female, 0.99},
+		{"ович", male, 0.99}, {"евич", male, 0.99}, {"ич", male, 0.97}, {"оглы", male, 0.99},
+		{"овна", female, 0.99}, {"евна", female, 0.99}, {"ична", female, 0.99}, {"кызы", female, 0.99},
+	})
+
+	surnameEndings = sortEndings([]ending{
+		{"ov", male, 0.95}, {"ev", male, 0.95}, {"yov", male, 0.95}, {"in", male, 0.85}, {"yn", male, 0.85},
+		{"sky", male, 0.95}, {"skiy", male, 0.95}, {"skii", male, 0.95}, {"skij", male, 0.95}, {"ski", male, 0.9},
+		{"tsky", male, 0.95}, {"tskiy", male, 0.95}, {"skoy", male, 0.95},
+		{"ova", female, 0.95}, {"eva", female, 0.95}, {"yova", female, 0.95}, {"ina", female, 0.85}, {"yna", female, 0.85},
+		{"skaya", female, 0.95}, {"skaja", female, 0.95}, {"tskaya", female, 0.95}, {"ska", female, 0.9},
+		{"ов", male, 0.95}, {"ев", male, 0.95}, {"ёв", male, 0.95}, {"ин", male, 0.85}, {"ын", male, 0.85},
+		{"ский", male, 0.95}, {"цкий", male, 0.95}, {"ской", male, 0.95},
+		{"ова", female, 0.95}, {"ева", female, 0.95}, {"ёва", female, 0.95}, {"ина", female, 0.85}, {"ына", female, 0.85},
+		{"ская", female, 0.95}, {"цкая", female, 0.95},
+	})
+)
+
+// Provider infers gender of Slavic names from patronymic and surname endings.
+// It ignores the first name, so it settles ambiguous names such as Sasha or Zhenya.
+// Surnames count only with a country hint or in Cyrillic, endings such as -in are
+// common in western surnames too. Queries without a telling ending get ErrInvalidName.
+type Provider struct{}
+
+func New() *Provider {
+	return &Provider{}
+}
+
+func (p *Provider) Gender(_ context.Context, query model.NameQuery) (model.GenderPrediction, error) {
+	const op = "client.person.rules.Gender"
+
+	if query.CountryID != "" && !slices.Contains(namingCountries, query.CountryID) {
+		return model.GenderPrediction{}, fmt.Errorf("%s: %w", op, personClient.ErrInvalidName)
+	}
+
+	byPatronymic, hasPatronymic := match(query.Patronymic, patronymicEndings)
+
+	var (
+		bySurname  ending
+		hasSurname bool
+	)
+	if query.CountryID != "" || cyrillic(query.Surname) {
+		bySurname, hasSurname = match(query.Surname, surnameEndings)
+	}
+
+	switch {
+	case hasPatronymic && hasSurname && byPatronymic.gender == bySurname.gender:
+		return prediction(byPatronymic.gender, agreedProbability), nil
+	case hasPatronymic && hasSurname:
+		// a foreign surname is likelier than a wrong patronymic
+		return prediction(byPatronymic.gender, byPatronymic.probability-bySurname.probability/2), nil
+	case hasPatronymic:
+		return prediction(byPatronymic.gender, byPatronymic.probability), nil
+	case hasSurname:
+		return prediction(bySurname.gender, bySurname.probability), nil
+	default:
+		return model.GenderPrediction{}, fmt.Errorf("%s: %w", op, personClient.ErrInvalidName)
+	}
+}
+
+func match(word string, endings []ending) (ending, bool) {
+	word = strings.ToLower(strings.TrimSpace(word))
+
+	for _, e := range endings {
+		// the ending alone is not a name
+		if len(word) > len(e.suffix) && strings.HasSuffix(word, e.suffix) {
+			return e, true
+		}
+	}
+
+	return ending{}, false
+}
+
+func cyrillic(word string) bool {
+	return strings.IndexFunc(word, func(r rune) bool {
+		return unicode.Is(unicode.Cyrillic, r)
+	}) >= 0
+}
+
+func prediction(gender string, probability float64) model.GenderPrediction {
+	return model.GenderPrediction{
+		Gender:      gender,
+		Probability: probability,
+	}
+}
+
+func sortEndings(endings []ending) []ending {
+	slices.SortStableFunc(endings, func(a, b ending) int {
+		return len(b.suffix) - len(a.suffix)
+	})
+
+	return endings
+}
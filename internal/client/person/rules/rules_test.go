@@ -0,0 +1,185 @@
+package rules
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+)
+
+func TestGender(t *testing.T) {
+	tests := []struct {
+		name            string
+		query           model.NameQuery
+		wantGender      string
+		wantProbability float64
+		wantInvalid     bool
+	}{
+		{
+			name:            "patronymic",
+			query:           model.NameQuery{Name: "Sasha", Patronymic: "Sergeevna"},
+			wantGender:      female,
+			wantProbability: 0.99,
+		},
+		{
+			name:            "cyrillic patronymic",
+			query:           model.NameQuery{Name: "Женя", Patronymic: "Олегович"},
+			wantGender:      male,
+			wantProbability: 0.99,
+		},
+		{
+			name:            "patronymic and surname agree",
+			query:           model.NameQuery{Name: "Sasha", Surname: "Ivanova", Patronymic: "Petrovna", CountryID: "RU"},
+			wantGender:      female,
+			wantProbability: agreedProbability,
+		},
+		{
+			name:            "patronymic wins over a foreign surname",
+			query:           model.NameQuery{Name: "Sasha", Surname: "Ivanova", Patronymic: "Petrovich", CountryID: "RU"},
+			wantGender:      male,
+			wantProbability: 0.99 - 0.95/2,
+		},
+		{
+			name:            "surname with a country hint",
+			query:           model.NameQuery{Name: "Zhenya", Surname: "Smirnova", CountryID: "RU"},
+			wantGender:      female,
+			wantProbability: 0.95,
+		},
+		{
+			name:            "short ending with a country hint",
+			query:           model.NameQuery{Name: "Sasha", Surname: "Pushkin", CountryID: "RU"},
+			wantGender:      male,
+			wantProbability: 0.85,
+		},
+		{
+			name:            "cyrillic surname without a hint",
+			query:           model.NameQuery{Name: "Саша", Surname: "Пушкина"},
+			wantGender:      female,
+			wantProbability: 0.85,
+		},
+		{
+			name:            "longest ending wins",
+			query:           model.NameQuery{Name: "Zhenya", Surname: "Dostoevskaya", CountryID: "UA"},
+			wantGender:      female,
+			wantProbability: 0.95,
+		},
+		{
+			name:        "latin surname without a hint",
+			query:       model.NameQuery{Name: "Anna", Surname: "Martin"},
+			wantInvalid: true,
+		},
+		{
+			name:        "western surname ending in -in",
+			query:       model.NameQuery{Name: "Ben", Surname: "Franklin"},
+			wantInvalid: true,
+		},
+		{
+			name:        "short western surname",
+			query:       model.NameQuery{Name: "Alina", Surname: "Lin"},
+			wantInvalid: true,
+		},
+		{
+			name:        "western surname ending in -ina",
+			query:       model.NameQuery{Name: "Alina", Surname: "Lina"},
+			wantInvalid: true,
+		},
+		{
+			name:        "other country hint",
+			query:       model.NameQuery{Name: "Anna", Surname: "Ivanova", Patronymic: "Petrovna", CountryID: "FR"},
+			wantInvalid: true,
+		},
+		{
+			name:        "ending alone is not a name",
+			query:       model.NameQuery{Name: "Li", Surname: "Ov", CountryID: "RU"},
+			wantInvalid: true,
+		},
+		{
+			name:        "nothing telling",
+			query:       model.NameQuery{Name: "Anna", Surname: "Kim", CountryID: "KZ"},
+			wantInvalid: true,
+		},
+	}
+
+	p := New()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Gender(context.Background(), tt.query)
+
+			if tt.wantInvalid {
+				require.ErrorIs(t, err, personClient.ErrInvalidName)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantGender, got.Gender)
+			assert.InDelta(t, tt.wantProbability, got.Probability, 1e-9)
+		})
+	}
+}
+
+type stubGender struct {
+	prediction model.GenderPrediction
+	err        error
+}
+
+func (s stubGender) Gender(context.Context, model.NameQuery) (model.GenderPrediction, error) {
+	return s.prediction, s.err
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   stubGender
+		query      model.NameQuery
+		wantGender string
+		wantSource string
+	}{
+		{
+			name:       "contradicted unsure answer is replaced",
+			provider:   stubGender{prediction: model.GenderPrediction{Gender: male, Probability: 0.6, Source: "genderize"}},
+			query:      model.NameQuery{Name: "Sasha", Patronymic: "Ivanovna"},
+			wantGender: female,
+			wantSource: model.SourceRules,
+		},
+		{
+			name:       "western name is left to the provider",
+			provider:   stubGender{prediction: model.GenderPrediction{Gender: female, Probability: 0.8, Source: "genderize"}},
+			query:      model.NameQuery{Name: "Anna", Surname: "Martin"},
+			wantGender: female,
+			wantSource: "genderize",
+		},
+		{
+			name:       "override is kept",
+			provider:   stubGender{prediction: model.GenderPrediction{Gender: male, Probability: 0.5, Source: model.SourceOverride}},
+			query:      model.NameQuery{Name: "Sasha", Patronymic: "Ivanovna"},
+			wantGender: male,
+			wantSource: model.SourceOverride,
+		},
+		{
+			name:       "unknown name is told by the rules",
+			provider:   stubGender{err: personClient.ErrInvalidName},
+			query:      model.NameQuery{Name: "Yaroslava", Surname: "Орлова"},
+			wantGender: female,
+			wantSource: model.SourceRules,
+		},
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCheck(log, tt.provider, New()).Gender(context.Background(), tt.query)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantGender, got.Gender)
+			assert.Equal(t, tt.wantSource, got.Source)
+		})
+	}
+}
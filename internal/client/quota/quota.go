@@ -0,0 +1,224 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/config"
+	"person-info/internal/domain/model"
+	"person-info/internal/lib/logger/sl"
+	"person-info/internal/storage"
+)
+
+// Rate limit headers sent by agify, genderize and nationalize
+const (
+	LimitHeader     = "X-Rate-Limit-Limit"
+	RemainingHeader = "X-Rate-Limit-Remaining"
+	ResetHeader     = "X-Rate-Limit-Reset"
+)
+
+type Storage interface {
+	ProviderUsage(ctx context.Context, provider string, day time.Time) (*model.ProviderUsage, error)
+	AddProviderUsage(ctx context.Context, delta *model.ProviderUsage) (*model.ProviderUsage, error)
+}
+
+// Quota counts names sent to a provider per UTC day in storage, so the count
+// survives restarts and is shared by every process calling the provider.
+type Quota struct {
+	log      *slog.Logger
+	storage  Storage
+	provider string
+	limit    int
+	reserve  int
+
+	mu    sync.Mutex
+	usage *model.ProviderUsage
+	// names of calls allowed but not recorded yet
+	reserved int
+}
+
+func New(log *slog.Logger, storage Storage, provider string, cfg config.QuotaConfig) *Quota {
+	return &Quota{
+		log:      log,
+		storage:  storage,
+		provider: provider,
+		limit:    cfg.Daily,
+		reserve:  cfg.Reserve,
+	}
+}
+
+// Allow refuses a call with a rate limit error when sending names would eat into the reserve.
+// Allowed names are reserved until the call is recorded or released, so concurrent calls
+// can't pass the check together.
+func (q *Quota) Allow(ctx context.Context, names int) error {
+	now := time.Now()
+	q.load(ctx, now)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	usage := q.usage
+
+	if usage.Limit == 0 || usage.Used()+q.reserved+names <= usage.Limit-q.reserve {
+		q.reserved += names
+		return nil
+	}
+
+	return fmt.Errorf("%d of %d daily names used: %w",
+		usage.Used()+q.reserved, usage.Limit, &personClient.RateLimitError{RetryAfter: resetIn(usage, now)})
+}
+
+// Release returns names reserved by Allow for a call that never reached the provider
+func (q *Quota) Release(names int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.reserved = max(q.reserved-names, 0)
+}
+
+// Record counts a call that reached the provider and releases its reservation,
+// names are counted only if the provider answered them
+func (q *Quota) Record(ctx context.Context, names int, resp *http.Response) {
+	const op = "client.quota.Record"
+
+	now := time.Now()
+
+	delta := model.ProviderUsage{
+		Provider: q.provider,
+		Day:      day(now),
+		Calls:    1,
+		Limit:    q.limit,
+	}
+
+	if resp.StatusCode < http.StatusBadRequest {
+		delta.Names = names
+	}
+
+	if limit, err := strconv.Atoi(resp.Header.Get(LimitHeader)); err == nil {
+		delta.Limit = limit
+	}
+
+	if remaining, err := strconv.Atoi(resp.Header.Get(RemainingHeader)); err == nil {
+		delta.Remaining = &remaining
+	}
+
+	if reset, err := strconv.Atoi(resp.Header.Get(ResetHeader)); err == nil {
+		resetAt := now.Add(time.Duration(reset) * time.Second)
+		delta.ResetAt = &resetAt
+	}
+
+	usage, err := q.storage.AddProviderUsage(context.WithoutCancel(ctx), &delta)
+	if err != nil {
+		q.log.Error("failed to save provider usage",
+			slog.String("op", op),
+			slog.String("provider", q.provider),
+			sl.Err(err),
+		)
+
+		q.load(ctx, now)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.reserved = max(q.reserved-names, 0)
+
+	if err != nil {
+		// keep counting in memory until storage is back
+		usage = q.usage
+		usage.Calls += delta.Calls
+		usage.Names += delta.Names
+		usage.Limit = delta.Limit
+		if delta.Remaining != nil {
+			usage.Remaining = delta.Remaining
+		}
+		if delta.ResetAt != nil {
+			usage.ResetAt = delta.ResetAt
+		}
+	}
+
+	q.usage = usage
+}
+
+// Usage reloads today's usage from storage, so calls made by other processes are included
+func (q *Quota) Usage(ctx context.Context) (*model.ProviderUsage, error) {
+	const op = "client.quota.Usage"
+
+	now := time.Now()
+
+	usage, err := q.storage.ProviderUsage(ctx, q.provider, day(now))
+	if err != nil {
+		if !errors.Is(err, storage.ErrUsageNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		usage = &model.ProviderUsage{Provider: q.provider, Day: day(now), Limit: q.limit}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.usage = usage
+
+	result := *usage
+	result.Degraded = usage.Limit > 0 && usage.Used() >= usage.Limit-q.reserve
+
+	return &result, nil
+}
+
+// load makes sure today's usage is known, reading it from storage after a restart or at midnight.
+// Storage is read without the lock, so a slow database doesn't hold up calls that only check it.
+func (q *Quota) load(ctx context.Context, now time.Time) {
+	const op = "client.quota.load"
+
+	today := day(now)
+
+	q.mu.Lock()
+	loaded := q.usage != nil && q.usage.Day.Equal(today)
+	q.mu.Unlock()
+
+	if loaded {
+		return
+	}
+
+	usage, err := q.storage.ProviderUsage(ctx, q.provider, today)
+	if err != nil {
+		if !errors.Is(err, storage.ErrUsageNotFound) {
+			q.log.Error("failed to load provider usage",
+				slog.String("op", op),
+				slog.String("provider", q.provider),
+				sl.Err(err),
+			)
+		}
+
+		usage = &model.ProviderUsage{Provider: q.provider, Day: today, Limit: q.limit}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// a concurrent call may have loaded or recorded today's usage meanwhile
+	if q.usage == nil || !q.usage.Day.Equal(today) {
+		q.usage = usage
+	}
+}
+
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// resetIn prefers the reset reported by the provider and falls back to the next UTC midnight
+func resetIn(usage *model.ProviderUsage, now time.Time) time.Duration {
+	if usage.ResetAt != nil && usage.ResetAt.After(now) {
+		return usage.ResetAt.Sub(now)
+	}
+
+	return day(now).Add(24 * time.Hour).Sub(now)
+}
@@ -0,0 +1,193 @@
+package quota
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/config"
+	"person-info/internal/domain/model"
+	"person-info/internal/storage"
+)
+
+type memoryStorage struct {
+	mu    sync.Mutex
+	usage *model.ProviderUsage
+}
+
+func (s *memoryStorage) ProviderUsage(_ context.Context, _ string, _ time.Time) (*model.ProviderUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.usage == nil {
+		return nil, storage.ErrUsageNotFound
+	}
+
+	usage := *s.usage
+	return &usage, nil
+}
+
+func (s *memoryStorage) AddProviderUsage(_ context.Context, delta *model.ProviderUsage) (*model.ProviderUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.usage == nil {
+		s.usage = &model.ProviderUsage{Provider: delta.Provider, Day: delta.Day}
+	}
+
+	s.usage.Calls += delta.Calls
+	s.usage.Names += delta.Names
+	s.usage.Limit = delta.Limit
+	if delta.Remaining != nil {
+		s.usage.Remaining = delta.Remaining
+	}
+
+	usage := *s.usage
+	return &usage, nil
+}
+
+func newQuota(daily, reserve int) *Quota {
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)), &memoryStorage{}, "agify",
+		config.QuotaConfig{Daily: daily, Reserve: reserve})
+}
+
+func okResponse() *http.Response {
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+}
+
+func TestAllowReservesNames(t *testing.T) {
+	q := newQuota(10, 2)
+	ctx := context.Background()
+
+	require.NoError(t, q.Allow(ctx, 5))
+	require.NoError(t, q.Allow(ctx, 3))
+
+	err := q.Allow(ctx, 1)
+	require.ErrorIs(t, err, personClient.ErrRateLimited)
+
+	var rateLimit *personClient.RateLimitError
+	require.ErrorAs(t, err, &rateLimit)
+	assert.Positive(t, rateLimit.RetryAfter)
+
+	q.Release(3)
+	require.NoError(t, q.Allow(ctx, 3))
+}
+
+func TestAllowConcurrent(t *testing.T) {
+	q := newQuota(100, 5)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if q.Allow(context.Background(), 10) == nil {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 9, allowed)
+}
+
+func TestRecordReleasesReservation(t *testing.T) {
+	q := newQuota(10, 0)
+	ctx := context.Background()
+
+	require.NoError(t, q.Allow(ctx, 4))
+	q.Record(ctx, 4, okResponse())
+
+	require.NoError(t, q.Allow(ctx, 6))
+	q.Record(ctx, 6, &http.Response{StatusCode: http.StatusBadGateway, Header: http.Header{}})
+
+	usage, err := q.Usage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, usage.Calls)
+	assert.Equal(t, 4, usage.Names)
+
+	require.NoError(t, q.Allow(ctx, 6))
+	require.Error(t, q.Allow(ctx, 1))
+}
+
+func TestRecordTrustsProviderHeaders(t *testing.T) {
+	q := newQuota(100, 5)
+	ctx := context.Background()
+
+	resp := okResponse()
+	resp.Header.Set(LimitHeader, "1000")
+	resp.Header.Set(RemainingHeader, "3")
+
+	require.NoError(t, q.Allow(ctx, 1))
+	q.Record(ctx, 1, resp)
+
+	usage, err := q.Usage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 997, usage.Used())
+	assert.True(t, usage.Degraded)
+
+	require.ErrorIs(t, q.Allow(ctx, 1), personClient.ErrRateLimited)
+}
+
+func TestUnlimited(t *testing.T) {
+	q := newQuota(0, 5)
+
+	for range 100 {
+		require.NoError(t, q.Allow(context.Background(), 100))
+	}
+}
+
+type slowStorage struct {
+	memoryStorage
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (s *slowStorage) ProviderUsage(ctx context.Context, provider string, day time.Time) (*model.ProviderUsage, error) {
+	close(s.entered)
+	<-s.release
+
+	return s.memoryStorage.ProviderUsage(ctx, provider, day)
+}
+
+func TestAllowReadsStorageWithoutLock(t *testing.T) {
+	slow := &slowStorage{entered: make(chan struct{}), release: make(chan struct{})}
+	q := New(slog.New(slog.NewTextHandler(io.Discard, nil)), slow, "agify", config.QuotaConfig{Daily: 10})
+
+	allowed := make(chan error)
+	go func() {
+		allowed <- q.Allow(context.Background(), 1)
+	}()
+
+	<-slow.entered
+
+	released := make(chan struct{})
+	go func() {
+		q.Release(0)
+		close(released)
+	}()
+
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("quota is locked while usage is read from storage")
+	}
+
+	close(slow.release)
+	require.NoError(t, <-allowed)
+}
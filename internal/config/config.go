@@ -8,9 +8,18 @@ import (
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+const (
+	LowConfidenceFlag   = "flag"
+	LowConfidenceReject = "reject"
+)
+
 type Config struct {
 	Server ServerConfig `env-prefix:"SERVER_" env-required:"true"`
 	DB     DBConfig     `env-prefix:"DB_" env-required:"true"`
+	Cache  CacheConfig  `env-prefix:"CACHE_"`
+
+	Providers  ProvidersConfig  `env-prefix:"PROVIDERS_"`
+	Enrichment EnrichmentConfig `env-prefix:"ENRICHMENT_"`
 }
 
 type ServerConfig struct {
@@ -28,6 +37,121 @@ type DBConfig struct {
 	Name     string `env:"NAME" env-required:"true"`
 }
 
+type CacheConfig struct {
+	TTL        time.Duration `env:"TTL" env-default:"720h"`
+	MemorySize int           `env:"MEMORY_SIZE" env-default:"10000"`
+	MemoryTTL  time.Duration `env:"MEMORY_TTL" env-default:"1h"`
+}
+
+type ProvidersConfig struct {
+	Agify       ProviderConfig `env-prefix:"AGIFY_"`
+	Genderize   ProviderConfig `env-prefix:"GENDERIZE_"`
+	Nationalize ProviderConfig `env-prefix:"NATIONALIZE_"`
+	Offline     OfflineConfig  `env-prefix:"OFFLINE_"`
+	Override    OverrideConfig `env-prefix:"OVERRIDE_"`
+	Rules       RulesConfig    `env-prefix:"RULES_"`
+	Ensemble    EnsembleConfig `env-prefix:"ENSEMBLE_"`
+	Learned     LearnedConfig  `env-prefix:"LEARNED_"`
+	Chain       ChainConfig    `env-prefix:"CHAIN_"`
+}
+
+// ChainConfig lists providers asked in order for each attribute.
+// A provider is skipped on error or when its answer is below the thresholds.
+type ChainConfig struct {
+	Age         []string `env:"AGE" env-default:"override,agify,offline"`
+	Gender      []string `env:"GENDER" env-default:"override,genderize,offline"`
+	Nationality []string `env:"NATIONALITY" env-default:"override,nationalize,offline"`
+
+	MinProbability float64 `env:"MIN_PROBABILITY" env-default:"0"`
+	MinCount       int     `env:"MIN_COUNT" env-default:"0"`
+}
+
+// EnsembleConfig combines providers into the "ensemble" provider that can be listed in a chain.
+// Members are given as provider:weight pairs, e.g. agify:2,offline:1; empty disables the ensemble.
+type EnsembleConfig struct {
+	Age         map[string]float64 `env:"AGE"`
+	Gender      map[string]float64 `env:"GENDER"`
+	Nationality map[string]float64 `env:"NATIONALITY"`
+}
+
+// LearnedConfig configures the "learned" provider answering from people already stored.
+// Names with fewer than MinSamples known values are left to the next provider.
+type LearnedConfig struct {
+	MinSamples      int           `env:"MIN_SAMPLES" env-default:"20"`
+	RefreshInterval time.Duration `env:"REFRESH_INTERVAL" env-default:"1h"`
+}
+
+// OverrideConfig configures the "override" provider. Overrides fed by edits of people
+// are used once MinCorrections edits agree, overrides set through the admin API always.
+type OverrideConfig struct {
+	MinCorrections int `env:"MIN_CORRECTIONS" env-default:"2"`
+}
+
+// RulesConfig configures gender rules for Slavic patronymics and surnames.
+// Listed in the gender chain before another provider the rules are a primary source,
+// after it a tie-breaker for its low confidence answers. Check verifies every answer of the chain.
+type RulesConfig struct {
+	Check bool `env:"CHECK" env-default:"false"`
+}
+
+type OfflineConfig struct {
+	Path string `env:"PATH" env-default:"data/names.csv"`
+}
+
+// ProviderConfig configures a single prediction API, empty URL means the public endpoint
+type ProviderConfig struct {
+	URL     string        `env:"URL"`
+	APIKey  string        `env:"APIKEY"`
+	Timeout time.Duration `env:"TIMEOUT" env-default:"5s"`
+	Enabled bool          `env:"ENABLED" env-default:"true"`
+
+	RetryCount   int           `env:"RETRY_COUNT" env-default:"2"`
+	RetryWait    time.Duration `env:"RETRY_WAIT" env-default:"200ms"`
+	RetryMaxWait time.Duration `env:"RETRY_MAX_WAIT" env-default:"2s"`
+
+	Breaker BreakerConfig `env-prefix:"BREAKER_"`
+	Quota   QuotaConfig   `env-prefix:"QUOTA_"`
+}
+
+type BreakerConfig struct {
+	Failures         int           `env:"FAILURES" env-default:"5"`
+	OpenTimeout      time.Duration `env:"OPEN_TIMEOUT" env-default:"30s"`
+	HalfOpenRequests int           `env:"HALF_OPEN_REQUESTS" env-default:"1"`
+}
+
+// QuotaConfig is the free tier allowance of a provider in names per UTC day, 0 means unlimited.
+// The provider is skipped once no more than Reserve names are left, so the chain falls back in time.
+type QuotaConfig struct {
+	Daily   int `env:"DAILY" env-default:"100"`
+	Reserve int `env:"RESERVE" env-default:"5"`
+}
+
+type EnrichmentConfig struct {
+	// Localize resolves nationality first and predicts age and gender for that country
+	Localize bool `env:"LOCALIZE" env-default:"false"`
+
+	// LowConfidencePolicy is either "flag" to store attributes below the thresholds
+	// as unknown or "reject" to fail the create
+	LowConfidencePolicy string `env:"LOW_CONFIDENCE_POLICY" env-default:"flag"`
+
+	Age         ThresholdConfig `env-prefix:"AGE_"`
+	Gender      ThresholdConfig `env-prefix:"GENDER_"`
+	Nationality ThresholdConfig `env-prefix:"NATIONALITY_"`
+
+	// Async stores people as pending and enriches them in background workers
+	Async        bool          `env:"ASYNC" env-default:"false"`
+	Workers      int           `env:"WORKERS" env-default:"4"`
+	QueueSize    int           `env:"QUEUE_SIZE" env-default:"1000"`
+	MaxAttempts  int           `env:"MAX_ATTEMPTS" env-default:"3"`
+	RetryBackoff time.Duration `env:"RETRY_BACKOFF" env-default:"5s"`
+}
+
+// ThresholdConfig sets the least confidence a prediction is accepted with, age has no probability
+type ThresholdConfig struct {
+	MinProbability float64 `env:"MIN_PROBABILITY" env-default:"0"`
+	MinCount       int     `env:"MIN_COUNT" env-default:"0"`
+}
+
 // MustLoad Load config file and panic if error occurs
 func MustLoad() *Config {
 	configPath := fetchConfigPath()
@@ -45,6 +169,12 @@ func MustLoad() *Config {
 		panic("failed to read config: " + err.Error())
 	}
 
+	switch cfg.Enrichment.LowConfidencePolicy {
+	case LowConfidenceFlag, LowConfidenceReject:
+	default:
+		panic("unknown low confidence policy: " + cfg.Enrichment.LowConfidencePolicy)
+	}
+
 	return &cfg
 }
 
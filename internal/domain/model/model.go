@@ -1,12 +1,221 @@
 package model
 
+import "time"
+
+// Unknown is stored instead of a gender or nationality predicted with too little confidence
+const Unknown = "unknown"
+
+// Predicted attributes of a person
+const (
+	FieldAge         = "age"
+	FieldGender      = "gender"
+	FieldNationality = "nationality"
+)
+
+const (
+	EnrichmentPending = "pending"
+	EnrichmentDone    = "done"
+	EnrichmentFailed  = "failed"
+)
+
 type Person struct {
+	ID          int64
 	Name        string
 	Surname     string
 	Patronymic  string
 	Age         int
 	Gender      string
 	Nationality string
+	// CountryHint is the requested country predictions are localized to
+	CountryHint string
+	Predictions *Predictions
+
+	EnrichmentStatus   string
+	EnrichmentAttempts int
+	EnrichmentError    string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Provenance tells where each predicted attribute came from, keyed by field
+	Provenance map[string]Provenance
+}
+
+// Ensemble explains a prediction combined from several providers. Disagreement is the
+// weighted standard deviation of member ages in years for age, and the weight share of
+// members whose answer differs from the result for gender and nationality.
+type Ensemble struct {
+	Members      []EnsembleMember
+	Disagreement float64
+}
+
+// EnsembleMember is the answer of a single provider, Share is its part of the total weight
+// of the members that answered. Members that failed have an Error and no share.
+type EnsembleMember struct {
+	Provider    string
+	Weight      float64
+	Share       float64
+	Answer      string
+	Probability float64
+	Error       string
+}
+
+// Provenance sources besides the prediction providers
+const (
+	SourceManual   = "manual"
+	SourceImport   = "import"
+	SourceOverride = "override"
+	SourceRules    = "rules"
+	SourceLearned  = "learned"
+)
+
+// Provenance records the source of an attribute value and the confidence it was set with.
+// Confidence is nil where the source gives none, such as age predictions.
+type Provenance struct {
+	Source     string
+	Confidence *float64
+	UpdatedAt  time.Time
+}
+
+// Manual reports whether the field was last set by hand
+func (p *Person) Manual(field string) bool {
+	return p.Provenance[field].Source == SourceManual
+}
+
+// PredictedFields lists predicted attributes set on the person
+func (p *Person) PredictedFields() []string {
+	var fields []string
+	if p.Age > 0 {
+		fields = append(fields, FieldAge)
+	}
+	if p.Gender != "" {
+		fields = append(fields, FieldGender)
+	}
+	if p.Nationality != "" {
+		fields = append(fields, FieldNationality)
+	}
+
+	return fields
+}
+
+// NameQuery describes whose attributes are predicted.
+// CountryID localizes age and gender predictions to a country when set.
+// Surname and Patronymic are optional, the name APIs ignore them.
+type NameQuery struct {
+	Name       string
+	Surname    string
+	Patronymic string
+	CountryID  string
+}
+
+// Predictions of each attribute record in Source the provider that answered.
+// LowConfidence marks predictions below the configured thresholds.
+type Predictions struct {
+	Age         AgePrediction
+	Gender      GenderPrediction
+	Nationality NationalityPrediction
+}
+
+func (p *Predictions) LowConfidence() bool {
+	return p.Age.LowConfidence || p.Gender.LowConfidence || p.Nationality.LowConfidence
+}
+
+type AgePrediction struct {
+	Age           int
+	Count         int
+	Source        string
+	LowConfidence bool
+	Ensemble      *Ensemble
+}
+
+func (p AgePrediction) Empty() bool {
+	return p.Age == 0
+}
+
+type GenderPrediction struct {
+	Gender        string
+	Probability   float64
+	Count         int
+	Source        string
+	LowConfidence bool
+	Ensemble      *Ensemble
+}
+
+func (p GenderPrediction) Empty() bool {
+	return p.Gender == ""
+}
+
+type CountryPrediction struct {
+	CountryID   string
+	Probability float64
+}
+
+// NationalityPrediction holds countries ranked by probability, most probable first
+type NationalityPrediction struct {
+	Countries     []CountryPrediction
+	Count         int
+	Source        string
+	LowConfidence bool
+	Ensemble      *Ensemble
+}
+
+func (n NationalityPrediction) Empty() bool {
+	return len(n.Countries) == 0
+}
+
+func (n NationalityPrediction) Top() CountryPrediction {
+	if len(n.Countries) == 0 {
+		return CountryPrediction{}
+	}
+
+	return n.Countries[0]
+}
+
+// NameOverride is an operator-corrected value of an attribute for everyone with the name.
+// Corrections counts agreeing manual edits of people that fed the override,
+// a pinned override was set through the admin API and is used regardless of them.
+type NameOverride struct {
+	Name        string
+	Field       string
+	Value       string
+	Corrections int
+	Pinned      bool
+	UpdatedAt   time.Time
+}
+
+// ProviderUsage counts names sent to a provider during a UTC day.
+// Remaining and ResetAt are reported by the provider in rate limit headers,
+// Limit is the reported or configured daily quota, 0 means unlimited.
+type ProviderUsage struct {
+	Provider  string
+	Day       time.Time
+	Calls     int
+	Names     int
+	Limit     int
+	Remaining *int
+	ResetAt   *time.Time
+	UpdatedAt time.Time
+
+	// Degraded is set while the provider is skipped to save the rest of the quota
+	Degraded bool
+}
+
+// Used trusts the provider's own count when it reported one
+func (u *ProviderUsage) Used() int {
+	if u.Remaining != nil && u.Limit > 0 {
+		return max(u.Names, u.Limit-*u.Remaining)
+	}
+
+	return u.Names
+}
+
+// Left is the number of names still allowed today, -1 when unlimited
+func (u *ProviderUsage) Left() int {
+	if u.Limit == 0 {
+		return -1
+	}
+
+	return max(u.Limit-u.Used(), 0)
 }
 
 type PeopleFilters struct {
@@ -16,6 +225,9 @@ type PeopleFilters struct {
 	Age         int
 	Gender      string
 	Nationality string
+	// LowConfidence selects people with (true) or without (false) low-confidence attributes
+	LowConfidence    *bool
+	EnrichmentStatus string
 }
 
 type Pagination struct {
@@ -0,0 +1,393 @@
+package fakeproviders
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/client/person/offline"
+	"person-info/internal/client/quota"
+	"person-info/internal/domain/model"
+)
+
+const (
+	Agify       = "agify"
+	Genderize   = "genderize"
+	Nationalize = "nationalize"
+
+	maxBatchSize = 10
+
+	// injectedRetryAfter is sent with injected rate limits, the quota itself is left intact
+	injectedRetryAfter = 1
+)
+
+// Faults controls injected misbehaviour, rates are probabilities in [0, 1]
+type Faults struct {
+	LatencyMS     int     `json:"latency_ms"`
+	ErrorRate     float64 `json:"error_rate"`
+	RateLimitRate float64 `json:"rate_limit_rate"`
+	NullRate      float64 `json:"null_rate"`
+}
+
+type Options struct {
+	Seed uint64
+	// Generate answers names missing from the dataset with values derived from the seed
+	Generate bool
+	// Quota is the number of names each provider answers per day, 0 means unlimited
+	Quota  int
+	Faults Faults
+}
+
+// Server imitates api.agify.io, api.genderize.io and api.nationalize.io
+type Server struct {
+	log      *slog.Logger
+	dataset  *offline.Provider
+	seed     uint64
+	generate bool
+	quota    int
+
+	mu      sync.Mutex
+	faults  Faults
+	rnd     *rand.Rand
+	usage   map[string]int
+	resetAt time.Time
+}
+
+func New(log *slog.Logger, dataset *offline.Provider, opts Options) *Server {
+	return &Server{
+		log:      log,
+		dataset:  dataset,
+		seed:     opts.Seed,
+		generate: opts.Generate,
+		quota:    opts.Quota,
+		faults:   opts.Faults,
+		rnd:      rand.New(rand.NewPCG(opts.Seed, opts.Seed)),
+		usage:    make(map[string]int),
+		resetAt:  nextDay(time.Now()),
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	g := gin.New()
+
+	g.Use(gin.Recovery())
+
+	g.GET("/"+Agify, s.handle(Agify, s.age))
+	g.GET("/"+Genderize, s.handle(Genderize, s.gender))
+	g.GET("/"+Nationalize, s.handle(Nationalize, s.nationality))
+
+	g.GET("/faults", s.getFaults)
+	g.PUT("/faults", s.setFaults)
+	g.DELETE("/usage", s.resetUsage)
+
+	return g
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handle(
+	provider string,
+	predict func(ctx context.Context, query model.NameQuery) any,
+) gin.HandlerFunc {
+	const op = "fakeproviders.handle"
+
+	return func(c *gin.Context) {
+		log := s.log.With(
+			slog.String("op", op),
+			slog.String("provider", provider),
+		)
+
+		names, batch := c.GetQueryArray("name[]")
+		if !batch {
+			name, ok := c.GetQuery("name")
+			if !ok || name == "" {
+				c.JSON(http.StatusUnprocessableEntity, errorResponse{Error: "Missing 'name' parameter"})
+				return
+			}
+			names = []string{name}
+		}
+
+		if len(names) > maxBatchSize {
+			c.JSON(http.StatusUnprocessableEntity, errorResponse{Error: "Invalid 'name' parameter"})
+			return
+		}
+
+		faults, roll := s.roll()
+
+		if faults.LatencyMS > 0 {
+			select {
+			case <-time.After(time.Duration(faults.LatencyMS) * time.Millisecond):
+			case <-c.Request.Context().Done():
+				return
+			}
+		}
+
+		if roll.err {
+			log.Debug("injecting error")
+
+			c.JSON(http.StatusServiceUnavailable, errorResponse{Error: "Service unavailable"})
+			return
+		}
+
+		remaining, reset, ok := s.consume(provider, len(names))
+		if s.quota > 0 {
+			c.Header(quota.LimitHeader, strconv.Itoa(s.quota))
+			c.Header(quota.RemainingHeader, strconv.Itoa(remaining))
+			c.Header(quota.ResetHeader, strconv.Itoa(reset))
+		}
+
+		if !ok || roll.rateLimit {
+			log.Debug("rejecting with rate limit", slog.Bool("injected", ok))
+
+			retryAfter := reset
+			if ok {
+				retryAfter = injectedRetryAfter
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+
+			c.JSON(http.StatusTooManyRequests, errorResponse{Error: "Request limit reached"})
+			return
+		}
+
+		countryID := c.Query("country_id")
+
+		results := make([]any, len(names))
+		for i, name := range names {
+			query := model.NameQuery{Name: name, CountryID: countryID}
+			if roll.null {
+				results[i] = nullResponse(provider, query)
+				continue
+			}
+			results[i] = predict(c.Request.Context(), query)
+		}
+
+		if batch {
+			c.JSON(http.StatusOK, results)
+			return
+		}
+
+		c.JSON(http.StatusOK, results[0])
+	}
+}
+
+type ageResponse struct {
+	Count     int     `json:"count"`
+	Name      string  `json:"name"`
+	Age       *int    `json:"age"`
+	CountryID *string `json:"country_id,omitempty"`
+}
+
+type genderResponse struct {
+	Count       int     `json:"count"`
+	Name        string  `json:"name"`
+	Gender      *string `json:"gender"`
+	Probability float64 `json:"probability"`
+	CountryID   *string `json:"country_id,omitempty"`
+}
+
+type countryResponse struct {
+	CountryID   string  `json:"country_id"`
+	Probability float64 `json:"probability"`
+}
+
+type nationalityResponse struct {
+	Count   int               `json:"count"`
+	Name    string            `json:"name"`
+	Country []countryResponse `json:"country"`
+}
+
+func (s *Server) age(ctx context.Context, query model.NameQuery) any {
+	prediction, err := s.dataset.Age(ctx, query)
+	if errors.Is(err, personClient.ErrInvalidName) && s.generate {
+		prediction = s.generated(query.Name).Age
+	}
+
+	resp := nullResponse(Agify, query).(ageResponse)
+	if !prediction.Empty() {
+		resp.Count = prediction.Count
+		resp.Age = &prediction.Age
+	}
+
+	return resp
+}
+
+func (s *Server) gender(ctx context.Context, query model.NameQuery) any {
+	prediction, err := s.dataset.Gender(ctx, query)
+	if errors.Is(err, personClient.ErrInvalidName) && s.generate {
+		prediction = s.generated(query.Name).Gender
+	}
+
+	resp := nullResponse(Genderize, query).(genderResponse)
+	if !prediction.Empty() {
+		resp.Count = prediction.Count
+		resp.Gender = &prediction.Gender
+		resp.Probability = prediction.Probability
+	}
+
+	return resp
+}
+
+func (s *Server) nationality(ctx context.Context, query model.NameQuery) any {
+	prediction, err := s.dataset.Nationality(ctx, query)
+	if errors.Is(err, personClient.ErrInvalidName) && s.generate {
+		prediction = s.generated(query.Name).Nationality
+	}
+
+	resp := nullResponse(Nationalize, query).(nationalityResponse)
+	resp.Count = prediction.Count
+	for _, c := range prediction.Countries {
+		resp.Country = append(resp.Country, countryResponse{
+			CountryID:   c.CountryID,
+			Probability: c.Probability,
+		})
+	}
+
+	return resp
+}
+
+func nullResponse(provider string, query model.NameQuery) any {
+	var countryID *string
+	if query.CountryID != "" {
+		countryID = &query.CountryID
+	}
+
+	switch provider {
+	case Agify:
+		return ageResponse{Name: query.Name, CountryID: countryID}
+	case Genderize:
+		return genderResponse{Name: query.Name, CountryID: countryID}
+	default:
+		return nationalityResponse{Name: query.Name, Country: []countryResponse{}}
+	}
+}
+
+var generatedCountries = []string{"RU", "UA", "BY", "KZ", "US", "DE", "PL", "RS"}
+
+// generated derives a stable prediction from the name and the seed,
+// so the same seed always yields the same answers
+func (s *Server) generated(name string) model.Predictions {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+
+	rnd := rand.New(rand.NewPCG(s.seed, h.Sum64()))
+
+	count := 100 + rnd.IntN(50000)
+
+	gender := "male"
+	if rnd.IntN(2) == 0 {
+		gender = "female"
+	}
+
+	first := rnd.IntN(len(generatedCountries))
+	second := (first + 1 + rnd.IntN(len(generatedCountries)-1)) % len(generatedCountries)
+	top := 0.3 + rnd.Float64()*0.5
+
+	return model.Predictions{
+		Age: model.AgePrediction{
+			Age:   18 + rnd.IntN(60),
+			Count: count,
+		},
+		Gender: model.GenderPrediction{
+			Gender:      gender,
+			Probability: 0.5 + float64(rnd.IntN(50))/100,
+			Count:       count,
+		},
+		Nationality: model.NationalityPrediction{
+			Countries: []model.CountryPrediction{
+				{CountryID: generatedCountries[first], Probability: top},
+				{CountryID: generatedCountries[second], Probability: (1 - top) / 2},
+			},
+			Count: count,
+		},
+	}
+}
+
+type outcome struct {
+	err       bool
+	rateLimit bool
+	null      bool
+}
+
+func (s *Server) roll() (Faults, outcome) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.faults, outcome{
+		err:       s.rnd.Float64() < s.faults.ErrorRate,
+		rateLimit: s.rnd.Float64() < s.faults.RateLimitRate,
+		null:      s.rnd.Float64() < s.faults.NullRate,
+	}
+}
+
+// consume charges names against the provider quota and reports
+// the remaining quota and seconds until it resets
+func (s *Server) consume(provider string, names int) (int, int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if !now.Before(s.resetAt) {
+		clear(s.usage)
+		s.resetAt = nextDay(now)
+	}
+
+	reset := int(s.resetAt.Sub(now).Seconds())
+
+	if s.quota == 0 {
+		return 0, reset, true
+	}
+
+	if s.usage[provider]+names > s.quota {
+		return s.quota - s.usage[provider], reset, false
+	}
+
+	s.usage[provider] += names
+
+	return s.quota - s.usage[provider], reset, true
+}
+
+func (s *Server) getFaults(c *gin.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c.JSON(http.StatusOK, s.faults)
+}
+
+func (s *Server) setFaults(c *gin.Context) {
+	var faults Faults
+	if err := c.ShouldBindJSON(&faults); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return
+	}
+
+	s.mu.Lock()
+	s.faults = faults
+	s.mu.Unlock()
+
+	s.log.Info("faults updated", slog.Any("faults", faults))
+
+	c.JSON(http.StatusOK, faults)
+}
+
+func (s *Server) resetUsage(c *gin.Context) {
+	s.mu.Lock()
+	clear(s.usage)
+	s.mu.Unlock()
+
+	c.Status(http.StatusNoContent)
+}
+
+func nextDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
@@ -0,0 +1,273 @@
+package fakeproviders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/client/person/genderize"
+	"person-info/internal/client/person/offline"
+	"person-info/internal/client/quota"
+	"person-info/internal/config"
+	"person-info/internal/domain/model"
+	"person-info/internal/storage"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func newServer(t *testing.T, opts Options) *httptest.Server {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	dataset, err := offline.Load(discard, "../../data/names.csv")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(New(discard, dataset, opts).Handler())
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func get(t *testing.T, srv *httptest.Server, path string) (*http.Response, string) {
+	t.Helper()
+
+	resp, err := http.Get(srv.URL + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp, string(body)
+}
+
+func setFaults(t *testing.T, srv *httptest.Server, faults Faults) {
+	t.Helper()
+
+	body, err := json.Marshal(faults)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPut, srv.URL+"/faults", bytes.NewReader(body))
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestSeedGivesStableAnswers(t *testing.T) {
+	opts := Options{Seed: 42, Generate: true, Faults: Faults{ErrorRate: 0.5, NullRate: 0.3}}
+
+	first, second := newServer(t, opts), newServer(t, opts)
+
+	paths := []string{
+		"/agify?name=Zyxwvut",
+		"/genderize?name=Zyxwvut",
+		"/nationalize?name=Zyxwvut",
+		"/genderize?name[]=Qwerty&name[]=Zyxwvut",
+	}
+
+	// injected faults are rolled from the seed too, so every response must repeat
+	for range 5 {
+		for _, path := range paths {
+			resp1, body1 := get(t, first, path)
+			resp2, body2 := get(t, second, path)
+
+			assert.Equal(t, resp1.StatusCode, resp2.StatusCode, path)
+			assert.JSONEq(t, body1, body2, path)
+		}
+	}
+
+	other := newServer(t, Options{Seed: 7, Generate: true})
+	stable := newServer(t, Options{Seed: 42, Generate: true})
+
+	_, want := get(t, stable, "/agify?name=Zyxwvut")
+	_, got := get(t, other, "/agify?name=Zyxwvut")
+	assert.NotEqual(t, want, got)
+}
+
+func TestFaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		faults     Faults
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "error rate",
+			faults:     Faults{ErrorRate: 1},
+			path:       "/agify?name=Anna",
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   `{"error": "Service unavailable"}`,
+		},
+		{
+			name:       "rate limit rate",
+			faults:     Faults{RateLimitRate: 1},
+			path:       "/genderize?name=Anna",
+			wantStatus: http.StatusTooManyRequests,
+			wantBody:   `{"error": "Request limit reached"}`,
+		},
+		{
+			name:       "null age",
+			faults:     Faults{NullRate: 1},
+			path:       "/agify?name=Anna",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"count": 0, "name": "Anna", "age": null}`,
+		},
+		{
+			name:       "null gender with country",
+			faults:     Faults{NullRate: 1},
+			path:       "/genderize?name=Anna&country_id=RU",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"count": 0, "name": "Anna", "gender": null, "probability": 0, "country_id": "RU"}`,
+		},
+		{
+			name:       "null nationality batch",
+			faults:     Faults{NullRate: 1},
+			path:       "/nationalize?name[]=Anna&name[]=Ivan",
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"count": 0, "name": "Anna", "country": []}, {"count": 0, "name": "Ivan", "country": []}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newServer(t, Options{Seed: 1})
+			setFaults(t, srv, tt.faults)
+
+			resp, body := get(t, srv, tt.path)
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.JSONEq(t, tt.wantBody, body)
+		})
+	}
+}
+
+func TestLatencyFault(t *testing.T) {
+	srv := newServer(t, Options{Seed: 1, Faults: Faults{LatencyMS: 50}})
+
+	start := time.Now()
+	resp, _ := get(t, srv, "/agify?name=Anna")
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+type memoryUsage struct {
+	mu    sync.Mutex
+	usage *model.ProviderUsage
+}
+
+func (s *memoryUsage) ProviderUsage(context.Context, string, time.Time) (*model.ProviderUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.usage == nil {
+		return nil, storage.ErrUsageNotFound
+	}
+
+	usage := *s.usage
+	return &usage, nil
+}
+
+func (s *memoryUsage) AddProviderUsage(_ context.Context, delta *model.ProviderUsage) (*model.ProviderUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.usage == nil {
+		s.usage = &model.ProviderUsage{Provider: delta.Provider, Day: delta.Day}
+	}
+
+	s.usage.Calls += delta.Calls
+	s.usage.Names += delta.Names
+	s.usage.Limit = delta.Limit
+	s.usage.Remaining = delta.Remaining
+	s.usage.ResetAt = delta.ResetAt
+
+	usage := *s.usage
+	return &usage, nil
+}
+
+func newClient(srv *httptest.Server) (*genderize.Client, *memoryUsage) {
+	usage := &memoryUsage{}
+
+	return genderize.New(discard, config.ProviderConfig{
+		URL:     srv.URL + "/" + Genderize,
+		Timeout: time.Second,
+		Enabled: true,
+		Breaker: config.BreakerConfig{Failures: 5, OpenTimeout: time.Minute, HalfOpenRequests: 1},
+	}, usage), usage
+}
+
+func TestRateLimitHeadersMatchClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("headers", func(t *testing.T) {
+		srv := newServer(t, Options{Seed: 1, Quota: 3})
+
+		resp, _ := get(t, srv, "/genderize?name[]=Anna&name[]=Ivan")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		assert.Equal(t, "3", resp.Header.Get(quota.LimitHeader))
+		assert.Equal(t, "1", resp.Header.Get(quota.RemainingHeader))
+
+		reset, err := strconv.Atoi(resp.Header.Get(quota.ResetHeader))
+		require.NoError(t, err)
+		assert.InDelta(t, time.Until(nextDay(time.Now())).Seconds(), reset, 2)
+
+		resp, _ = get(t, srv, "/genderize?name[]=Anna&name[]=Ivan")
+		require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+		assert.Equal(t, "1", resp.Header.Get(quota.RemainingHeader))
+		assert.Equal(t, resp.Header.Get(quota.ResetHeader), resp.Header.Get("Retry-After"))
+	})
+
+	t.Run("exhausted quota", func(t *testing.T) {
+		srv := newServer(t, Options{Seed: 1, Quota: 1})
+		client, usage := newClient(srv)
+
+		_, err := client.Gender(ctx, model.NameQuery{Name: "Anna"})
+		require.NoError(t, err)
+
+		require.NotNil(t, usage.usage.Remaining)
+		assert.Equal(t, 0, *usage.usage.Remaining)
+		assert.Equal(t, 1, usage.usage.Limit)
+
+		_, err = client.Gender(ctx, model.NameQuery{Name: "Ivan"})
+
+		var rateLimit *personClient.RateLimitError
+		require.ErrorAs(t, err, &rateLimit)
+		assert.InDelta(t, time.Until(nextDay(time.Now())).Seconds(), rateLimit.RetryAfter.Seconds(), 2)
+
+		// the client stops calling once the server reported no names left
+		assert.Equal(t, 1, usage.usage.Calls)
+	})
+
+	t.Run("injected rate limit", func(t *testing.T) {
+		srv := newServer(t, Options{Seed: 1, Quota: 100, Faults: Faults{RateLimitRate: 1}})
+		client, _ := newClient(srv)
+
+		_, err := client.Gender(ctx, model.NameQuery{Name: "Anna"})
+
+		var rateLimit *personClient.RateLimitError
+		require.ErrorAs(t, err, &rateLimit)
+		assert.Equal(t, injectedRetryAfter*time.Second, rateLimit.RetryAfter)
+	})
+}
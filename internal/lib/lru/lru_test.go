@@ -0,0 +1,68 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[string, int](2, time.Hour)
+
+	c.Set("anna", 1)
+	c.Set("boris", 2)
+
+	// reading anna makes boris the oldest
+	_, _ = c.Get("anna")
+	c.Set("vera", 3)
+
+	_, ok := c.Get("boris")
+	assert.False(t, ok)
+
+	value, ok := c.Get("anna")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestSetReplacesValue(t *testing.T) {
+	c := New[string, int](2, time.Hour)
+
+	c.Set("anna", 1)
+	c.Set("anna", 2)
+
+	value, ok := c.Get("anna")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestExpires(t *testing.T) {
+	c := New[string, int](10, 10*time.Millisecond)
+
+	c.Set("anna", 1)
+	time.Sleep(20 * time.Millisecond)
+
+	_, ok := c.Get("anna")
+	assert.False(t, ok)
+	assert.Zero(t, c.Len())
+}
+
+func TestDelete(t *testing.T) {
+	c := New[string, int](0, time.Hour)
+
+	for i, name := range []string{"anna", "anton", "boris"} {
+		c.Set(name, i)
+	}
+
+	c.Delete("boris")
+	n := c.DeleteFunc(func(key string) bool { return key[0] == 'a' })
+
+	assert.Equal(t, 2, n)
+	assert.Zero(t, c.Len())
+
+	c.Set("vera", 1)
+	c.Purge()
+	assert.Zero(t, c.Len())
+}
@@ -0,0 +1,140 @@
+package override
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"person-info/internal/client/person/override"
+	"person-info/internal/domain/model"
+	"person-info/internal/lib/logger/sl"
+	"person-info/internal/storage"
+	"person-info/internal/transport/dto"
+)
+
+type Storage interface {
+	NameOverrides(ctx context.Context, name string) ([]*model.NameOverride, error)
+	SaveNameOverride(ctx context.Context, name, field, value string) (*model.NameOverride, error)
+	DeleteNameOverride(ctx context.Context, name, field string) error
+}
+
+var (
+	ErrOverrideNotFound = errors.New("name override not found")
+	ErrInvalidField     = errors.New("invalid override field")
+	ErrInvalidValue     = errors.New("invalid override value")
+)
+
+var (
+	genders   = []string{"male", "female"}
+	countryID = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+const maxAge = 150
+
+type Service struct {
+	log     *slog.Logger
+	storage Storage
+}
+
+func New(log *slog.Logger, storage Storage) *Service {
+	return &Service{
+		log:     log,
+		storage: storage,
+	}
+}
+
+func (s *Service) Overrides(ctx context.Context, name string) ([]*dto.NameOverrideResponse, error) {
+	const op = "service.override.Overrides"
+
+	overrides, err := s.storage.NameOverrides(ctx, override.Normalize(name))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return dto.ToNameOverridesResponse(overrides), nil
+}
+
+func (s *Service) Save(ctx context.Context, name, field, value string) (*dto.NameOverrideResponse, error) {
+	const op = "service.override.Save"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("name", name),
+		slog.String("field", field),
+	)
+
+	value, err := NormalizeValue(field, value)
+	if err != nil {
+		log.Info("invalid override", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	o, err := s.storage.SaveNameOverride(ctx, override.Normalize(name), field, value)
+	if err != nil {
+		log.Error("failed to save override", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("override saved", slog.String("value", value))
+
+	return dto.ToNameOverrideResponse(o), nil
+}
+
+func (s *Service) Delete(ctx context.Context, name, field string) error {
+	const op = "service.override.Delete"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("name", name),
+		slog.String("field", field),
+	)
+
+	if err := s.storage.DeleteNameOverride(ctx, override.Normalize(name), field); err != nil {
+		if errors.Is(err, storage.ErrOverrideNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrOverrideNotFound)
+		}
+
+		log.Error("failed to delete override", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("override deleted")
+
+	return nil
+}
+
+// NormalizeValue validates an override value and brings it to the form providers answer with
+func NormalizeValue(field, value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	switch field {
+	case model.FieldAge:
+		age, err := strconv.Atoi(value)
+		if err != nil || age < 1 || age > maxAge {
+			return "", fmt.Errorf("%w: age must be between 1 and %d", ErrInvalidValue, maxAge)
+		}
+		return strconv.Itoa(age), nil
+	case model.FieldGender:
+		value = strings.ToLower(value)
+		if !slices.Contains(genders, value) {
+			return "", fmt.Errorf("%w: gender must be one of %s", ErrInvalidValue, strings.Join(genders, ", "))
+		}
+		return value, nil
+	case model.FieldNationality:
+		value = strings.ToUpper(value)
+		if !countryID.MatchString(value) {
+			return "", fmt.Errorf("%w: nationality must be an ISO 3166-1 alpha-2 code", ErrInvalidValue)
+		}
+		return value, nil
+	default:
+		return "", ErrInvalidField
+	}
+}
@@ -0,0 +1,271 @@
+package person
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+	"person-info/internal/lib/logger/sl"
+	"person-info/internal/storage"
+	"person-info/internal/transport/dto"
+)
+
+type enrichmentJob struct {
+	id      int64
+	attempt int
+}
+
+// Accept stores the person as pending and leaves enrichment to the background workers
+func (s *Service) Accept(
+	ctx context.Context,
+	personReq *dto.CreatePersonRequest,
+) (*dto.AcceptedResponse, error) {
+	const op = "service.person.Accept"
+
+	log := s.log.With(slog.String("op", op))
+
+	log.Info("accepting person")
+
+	person := dto.CreateReqToPersonModel(personReq)
+
+	exists, err := s.storage.PersonExists(ctx, person)
+	if err != nil {
+		log.Error("failed check if person exists", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if exists {
+		log.Error("person already exists")
+
+		return nil, fmt.Errorf("%s: %w", op, ErrPersonExists)
+	}
+
+	person.EnrichmentStatus = model.EnrichmentPending
+
+	if err := s.storage.SavePerson(ctx, person); err != nil {
+		log.Error("failed to create person", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.enqueue(ctx, enrichmentJob{id: person.ID})
+
+	log.Info("person accepted", slog.Int64("id", person.ID))
+
+	return &dto.AcceptedResponse{
+		ID:               person.ID,
+		EnrichmentStatus: person.EnrichmentStatus,
+	}, nil
+}
+
+// Retry queues a failed enrichment again
+func (s *Service) Retry(ctx context.Context, id int64) (*dto.AcceptedResponse, error) {
+	const op = "service.person.Retry"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	log.Info("retrying enrichment")
+
+	person, err := s.storage.RetryEnrichment(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrPersonNotFound):
+			log.Info("person not found")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrPersonNotFound)
+		case errors.Is(err, storage.ErrEnrichmentNotFailed):
+			log.Info("enrichment has not failed")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrNotRetryable)
+		default:
+			log.Error("failed to reset enrichment", sl.Err(err))
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	s.enqueue(ctx, enrichmentJob{id: person.ID})
+
+	return &dto.AcceptedResponse{
+		ID:               person.ID,
+		EnrichmentStatus: person.EnrichmentStatus,
+	}, nil
+}
+
+// Start runs enrichment workers until ctx is done and queues people left pending by a previous run
+func (s *Service) Start(ctx context.Context) error {
+	const op = "service.person.Start"
+
+	log := s.log.With(slog.String("op", op))
+
+	for range s.cfg.Workers {
+		s.workers.Add(1)
+		go func() {
+			defer s.workers.Done()
+
+			s.work(ctx)
+		}()
+	}
+
+	pending, err := s.storage.PendingPeople(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// the backlog may be larger than the queue, it's fed as the workers free up room
+	go func() {
+		for _, person := range pending {
+			select {
+			case <-ctx.Done():
+				return
+			case s.queue <- enrichmentJob{id: person.ID}:
+			}
+		}
+	}()
+
+	log.Info("enrichment workers started",
+		slog.Int("workers", s.cfg.Workers),
+		slog.Int("pending", len(pending)),
+	)
+
+	return nil
+}
+
+// Wait blocks until the workers finish their current jobs after shutdown
+func (s *Service) Wait() {
+	s.workers.Wait()
+}
+
+func (s *Service) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-s.queue:
+			s.process(ctx, job)
+		}
+	}
+}
+
+func (s *Service) process(ctx context.Context, job enrichmentJob) {
+	const op = "service.person.process"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("id", job.id),
+		slog.Int("attempt", job.attempt+1),
+	)
+
+	err := s.enrichPerson(ctx, job)
+	if err == nil {
+		log.Info("person enriched")
+		return
+	}
+
+	if ctx.Err() != nil {
+		// the person stays pending and is picked up on the next start
+		return
+	}
+
+	if errors.Is(err, storage.ErrPersonNotFound) {
+		log.Info("person deleted before enrichment")
+		return
+	}
+
+	var rateLimit *personClient.RateLimitError
+	if errors.As(err, &rateLimit) {
+		// waiting for a provider quota to reset doesn't use up an attempt
+		log.Warn("provider quota exhausted, enrichment postponed",
+			slog.Duration("retry_after", rateLimit.RetryAfter), sl.Err(err))
+
+		if err := s.storage.SaveEnrichmentFailure(ctx, job.id, model.EnrichmentPending, err.Error()); err != nil {
+			log.Error("failed to save enrichment failure", sl.Err(err))
+		}
+
+		time.AfterFunc(max(rateLimit.RetryAfter, s.cfg.RetryBackoff), func() {
+			s.enqueue(ctx, job)
+		})
+		return
+	}
+
+	log.Error("failed to enrich person", sl.Err(err))
+
+	job.attempt++
+
+	if retryable(err) && job.attempt < s.cfg.MaxAttempts {
+		if err := s.storage.SaveEnrichmentFailure(ctx, job.id, model.EnrichmentPending, err.Error()); err != nil {
+			log.Error("failed to save enrichment failure", sl.Err(err))
+		}
+
+		time.AfterFunc(s.cfg.RetryBackoff<<(job.attempt-1), func() {
+			s.enqueue(ctx, job)
+		})
+		return
+	}
+
+	if err := s.storage.SaveEnrichmentFailure(ctx, job.id, model.EnrichmentFailed, err.Error()); err != nil {
+		log.Error("failed to save enrichment failure", sl.Err(err))
+	}
+}
+
+// enrichPerson reads the person when the job runs, so edits made while it was queued are kept
+func (s *Service) enrichPerson(ctx context.Context, job enrichmentJob) error {
+	person, err := s.storage.PersonByID(ctx, job.id)
+	if err != nil {
+		return err
+	}
+
+	predictions, err := s.enrich(ctx, queryOf(person))
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.applyEnrichment(person, predictions, false); err != nil {
+		return err
+	}
+
+	return s.storage.SaveEnrichment(ctx, person)
+}
+
+// enqueue hands the job to the workers. When the queue is full the job waits for room
+// in the background, the person stays pending and is picked up on the next start at worst.
+func (s *Service) enqueue(ctx context.Context, job enrichmentJob) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	select {
+	case s.queue <- job:
+	default:
+		s.log.Warn("enrichment queue is full, job waits for room", slog.Int64("id", job.id))
+
+		go func() {
+			select {
+			case <-ctx.Done():
+			case s.queue <- job:
+			}
+		}()
+	}
+}
+
+// retryable reports whether another attempt may succeed, unknown names and rejected predictions won't
+func retryable(err error) bool {
+	return !errors.Is(err, personClient.ErrInvalidName) && !errors.Is(err, ErrLowConfidence)
+}
+
+func queryOf(person *model.Person) model.NameQuery {
+	return model.NameQuery{
+		Name:       person.Name,
+		Surname:    person.Surname,
+		Patronymic: person.Patronymic,
+		CountryID:  person.CountryHint,
+	}
+}
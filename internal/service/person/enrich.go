@@ -0,0 +1,260 @@
+package person
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/domain/model"
+	"person-info/internal/lib/logger/sl"
+	"person-info/internal/storage"
+	"person-info/internal/transport/dto"
+)
+
+const reenrichChunkSize = 100
+
+// Enrich re-runs the providers for a stored person and reports what changed.
+// Attributes set by hand are overwritten only if force is set.
+func (s *Service) Enrich(ctx context.Context, id int64, force bool) (*dto.EnrichmentDiffResponse, error) {
+	const op = "service.person.Enrich"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	log.Info("re-enriching person")
+
+	person, err := s.storage.PersonByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrPersonNotFound) {
+			log.Info("person not found")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrPersonNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	diffs, err := s.reenrich(ctx, []*model.Person{person}, force)
+	if err != nil {
+		log.Error("failed to re-enrich person", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return diffs[0], nil
+}
+
+// Provenance tells where each predicted attribute of a person came from
+func (s *Service) Provenance(ctx context.Context, id int64) (*dto.ProvenanceResponse, error) {
+	const op = "service.person.Provenance"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	person, err := s.storage.PersonByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrPersonNotFound) {
+			log.Info("person not found")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrPersonNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return dto.ToProvenanceResponse(person), nil
+}
+
+// EnrichPeople re-runs the providers for people selected like in People,
+// without a page size it goes through every matching person
+func (s *Service) EnrichPeople(ctx context.Context,
+	filters *dto.PeopleFilters,
+	pagination *dto.Pagination,
+	sorting *dto.SortOptions,
+	force bool,
+) (*dto.BulkEnrichmentResponse, error) {
+	const op = "service.person.EnrichPeople"
+
+	log := s.log.With(slog.String("op", op))
+
+	people, err := s.storage.People(ctx,
+		dto.ToPeopleFiltersModel(filters),
+		dto.ToPaginationModel(pagination),
+		dto.ToSortOptionsModel(sorting),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("re-enriching people", slog.Int("people", len(people)))
+
+	resp := &dto.BulkEnrichmentResponse{
+		People: make([]*dto.EnrichmentDiffResponse, 0, len(people)),
+	}
+
+	for chunk := range slices.Chunk(people, reenrichChunkSize) {
+		diffs, err := s.reenrich(ctx, chunk, force)
+		if err != nil {
+			log.Error("failed to re-enrich people", sl.Err(err))
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, diff := range diffs {
+			resp.Processed++
+
+			switch {
+			case diff.Error != "":
+				resp.Failed++
+			case len(diff.Changes) > 0:
+				resp.Changed++
+			}
+		}
+
+		resp.People = append(resp.People, diffs...)
+	}
+
+	log.Info("people re-enriched",
+		slog.Int("changed", resp.Changed),
+		slog.Int("failed", resp.Failed),
+	)
+
+	return resp, nil
+}
+
+// reenrich predicts attributes for people in one batch and stores them,
+// attributes edited by hand keep their values unless forced.
+// Per-person failures are reported in the diffs.
+func (s *Service) reenrich(
+	ctx context.Context,
+	people []*model.Person,
+	force bool,
+) ([]*dto.EnrichmentDiffResponse, error) {
+	var queries []model.NameQuery
+	for _, person := range people {
+		if query := queryOf(person); !slices.Contains(queries, query) {
+			queries = append(queries, query)
+		}
+	}
+
+	predictions, err := s.enrichBatch(ctx, queries)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make([]*dto.EnrichmentDiffResponse, len(people))
+	for i, person := range people {
+		diff := &dto.EnrichmentDiffResponse{
+			ID:      person.ID,
+			Changes: []dto.FieldChangeResponse{},
+		}
+		diffs[i] = diff
+
+		p, ok := predictions[queryOf(person)]
+		if !ok {
+			diff.Error = personClient.ErrInvalidName.Error()
+			continue
+		}
+
+		old := *person
+
+		diff.Kept, err = s.applyEnrichment(person, p, force)
+		if err != nil {
+			diff.Error = err.Error()
+			continue
+		}
+
+		// people saved before stay saved, so a failure is reported for the person alone
+		if err := s.storage.SaveEnrichment(ctx, person); err != nil {
+			s.log.Error("failed to save enrichment", slog.Int64("id", person.ID), sl.Err(err))
+
+			diff.Error = err.Error()
+			continue
+		}
+
+		diff.Changes = changes(&old, person)
+	}
+
+	return diffs, nil
+}
+
+// applyEnrichment applies predictions to a stored person, attributes edited by hand
+// keep their values unless forced. It returns the names of the kept attributes.
+func (s *Service) applyEnrichment(person *model.Person, predictions *model.Predictions, force bool) ([]string, error) {
+	old := *person
+
+	if err := s.applyPredictions(person, predictions); err != nil {
+		return nil, err
+	}
+
+	if force {
+		return nil, nil
+	}
+
+	return keepManualFields(person, &old), nil
+}
+
+// keepManualFields restores attributes edited by hand along with their stored
+// prediction details, so the new answers don't contradict them, and returns their names
+func keepManualFields(person, old *model.Person) []string {
+	stored := old.Predictions
+	if stored == nil {
+		stored = &model.Predictions{}
+	}
+
+	var kept []string
+	for _, field := range []string{model.FieldAge, model.FieldGender, model.FieldNationality} {
+		if !old.Manual(field) {
+			continue
+		}
+
+		kept = append(kept, field)
+		person.Provenance[field] = old.Provenance[field]
+
+		switch field {
+		case model.FieldAge:
+			person.Age = old.Age
+			person.Predictions.Age = stored.Age
+			person.Predictions.Age.LowConfidence = false
+		case model.FieldGender:
+			person.Gender = old.Gender
+			person.Predictions.Gender = stored.Gender
+			person.Predictions.Gender.LowConfidence = false
+		case model.FieldNationality:
+			person.Nationality = old.Nationality
+			person.Predictions.Nationality = stored.Nationality
+			person.Predictions.Nationality.LowConfidence = false
+		}
+	}
+
+	return kept
+}
+
+func changes(old, person *model.Person) []dto.FieldChangeResponse {
+	changes := []dto.FieldChangeResponse{}
+
+	if old.Age != person.Age {
+		changes = append(changes, dto.FieldChangeResponse{Field: model.FieldAge, Old: old.Age, New: person.Age})
+	}
+
+	if old.Gender != person.Gender {
+		changes = append(changes, dto.FieldChangeResponse{Field: model.FieldGender, Old: old.Gender, New: person.Gender})
+	}
+
+	if old.Nationality != person.Nationality {
+		changes = append(changes, dto.FieldChangeResponse{
+			Field: model.FieldNationality,
+			Old:   old.Nationality,
+			New:   person.Nationality,
+		})
+	}
+
+	return changes
+}
@@ -5,9 +5,19 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
+	"golang.org/x/sync/errgroup"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/client/person/override"
+	"person-info/internal/config"
 	"person-info/internal/domain/model"
 	"person-info/internal/lib/logger/sl"
+	overrideService "person-info/internal/service/override"
 	"person-info/internal/storage"
 	"person-info/internal/transport/dto"
 )
@@ -22,24 +32,38 @@ type Storage interface {
 		pagination *model.Pagination,
 		sort *model.SortOptions,
 	) ([]*model.Person, error)
+	PersonByID(ctx context.Context, id int64) (*model.Person, error)
+	PendingPeople(ctx context.Context) ([]*model.Person, error)
+	SaveEnrichment(ctx context.Context, person *model.Person) error
+	SaveEnrichmentFailure(ctx context.Context, id int64, status, reason string) error
+	RetryEnrichment(ctx context.Context, id int64) (*model.Person, error)
+	RecordCorrection(ctx context.Context, name, field, value string) error
 }
 
 type AgeProvider interface {
-	Age(ctx context.Context, name string) (int, error)
+	Age(ctx context.Context, query model.NameQuery) (model.AgePrediction, error)
 }
 
 type GenderProvider interface {
-	Gender(ctx context.Context, name string) (string, error)
+	Gender(ctx context.Context, query model.NameQuery) (model.GenderPrediction, error)
 }
 
 type NationalityProvider interface {
-	Nationality(ctx context.Context, name string) (string, error)
+	Nationality(ctx context.Context, query model.NameQuery) (model.NationalityPrediction, error)
+}
+
+// SaveResult is the outcome of saving a single person from a batch
+type SaveResult struct {
+	Person *dto.PersonResponse
+	Err    error
 }
 
 var (
 	ErrPersonExists    = errors.New("person already exists")
 	ErrPersonNotFound  = errors.New("person not found")
 	ErrNoUpdatedFields = errors.New("no updated fields")
+	ErrLowConfidence   = errors.New("prediction confidence is too low")
+	ErrNotRetryable    = errors.New("enrichment has not failed")
 )
 
 type Service struct {
@@ -48,6 +72,9 @@ type Service struct {
 	ageProvider         AgeProvider
 	genderProvider      GenderProvider
 	nationalityProvider NationalityProvider
+	cfg                 config.EnrichmentConfig
+	queue               chan enrichmentJob
+	workers             sync.WaitGroup
 }
 
 func New(
@@ -56,6 +83,7 @@ func New(
 	ageProvider AgeProvider,
 	genderProvider GenderProvider,
 	nationalityProvider NationalityProvider,
+	cfg config.EnrichmentConfig,
 ) *Service {
 	return &Service{
 		log:                 log,
@@ -63,6 +91,8 @@ func New(
 		ageProvider:         ageProvider,
 		genderProvider:      genderProvider,
 		nationalityProvider: nationalityProvider,
+		cfg:                 cfg,
+		queue:               make(chan enrichmentJob, cfg.QueueSize),
 	}
 }
 
@@ -91,30 +121,20 @@ func (s *Service) Save(
 		return nil, fmt.Errorf("%s: %w", op, ErrPersonExists)
 	}
 
-	age, err := s.ageProvider.Age(ctx, person.Name)
+	predictions, err := s.enrich(ctx, dto.CreateReqToNameQuery(personReq))
 	if err != nil {
-		log.Error("failed to get age", sl.Err(err))
+		log.Error("failed to enrich person", sl.Err(err))
 
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	gender, err := s.genderProvider.Gender(ctx, person.Name)
-	if err != nil {
-		log.Error("failed to get gender", sl.Err(err))
+	if err := s.applyPredictions(person, predictions); err != nil {
+		log.Error("prediction rejected", sl.Err(err))
 
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	nationality, err := s.nationalityProvider.Nationality(ctx, person.Name)
-	if err != nil {
-		log.Error("failed to get nationality", sl.Err(err))
-
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
-
-	person.Age = age
-	person.Gender = gender
-	person.Nationality = nationality
+	person.EnrichmentStatus = model.EnrichmentDone
 
 	if err := s.storage.SavePerson(ctx, person); err != nil {
 		log.Error("failed to create person", sl.Err(err))
@@ -127,6 +147,93 @@ func (s *Service) Save(
 	return dto.ToPersonResponse(person), nil
 }
 
+// SaveBatch saves several people resolving predictions for all distinct names at once.
+// Per-person failures are reported in the results, the error is returned only if the batch
+// can't proceed, which happens before anyone is saved.
+func (s *Service) SaveBatch(
+	ctx context.Context,
+	personReqs []*dto.CreatePersonRequest,
+) ([]*SaveResult, error) {
+	const op = "service.person.SaveBatch"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int("people", len(personReqs)),
+	)
+
+	log.Info("saving people")
+
+	results := make([]*SaveResult, len(personReqs))
+	people := make([]*model.Person, len(personReqs))
+	type fullName struct{ name, surname, patronymic string }
+	seen := make(map[fullName]struct{}, len(personReqs))
+
+	var queries []model.NameQuery
+	for i, req := range personReqs {
+		person := dto.CreateReqToPersonModel(req)
+
+		exists, err := s.storage.PersonExists(ctx, person)
+		if err != nil {
+			log.Error("failed check if person exists", sl.Err(err))
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		key := fullName{person.Name, person.Surname, person.Patronymic}
+		if _, dup := seen[key]; exists || dup {
+			results[i] = &SaveResult{Err: ErrPersonExists}
+			continue
+		}
+		seen[key] = struct{}{}
+
+		people[i] = person
+
+		if query := dto.CreateReqToNameQuery(req); !slices.Contains(queries, query) {
+			queries = append(queries, query)
+		}
+	}
+
+	predictions, err := s.enrichBatch(ctx, queries)
+	if err != nil {
+		log.Error("failed to enrich people", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i, person := range people {
+		if person == nil {
+			continue
+		}
+
+		p, ok := predictions[dto.CreateReqToNameQuery(personReqs[i])]
+		if !ok {
+			results[i] = &SaveResult{Err: personClient.ErrInvalidName}
+			continue
+		}
+
+		if err := s.applyPredictions(person, p); err != nil {
+			results[i] = &SaveResult{Err: err}
+			continue
+		}
+
+		person.EnrichmentStatus = model.EnrichmentDone
+
+		// people saved before stay saved, so a failure is reported for the person alone
+		if err := s.storage.SavePerson(ctx, person); err != nil {
+			log.Error("failed to create person", slog.Int("index", i), sl.Err(err))
+
+			results[i] = &SaveResult{Err: fmt.Errorf("%s: %w", op, err)}
+			continue
+		}
+
+		results[i] = &SaveResult{Person: dto.ToPersonResponse(person)}
+	}
+
+	log.Info("people saved successfully")
+
+	return results, nil
+}
+
 func (s *Service) Update(
 	ctx context.Context,
 	id int64,
@@ -141,7 +248,32 @@ func (s *Service) Update(
 
 	log.Info("updating person")
 
-	updatedPerson, err := s.storage.UpdatePerson(ctx, id, dto.UpdateReqToPersonModel(person))
+	update := dto.UpdateReqToPersonModel(person)
+
+	previous, err := s.storage.PersonByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrPersonNotFound) {
+			log.Info("person not found")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrPersonNotFound)
+		}
+
+		log.Error("failed to get person", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	manual := 1.0
+	update.Provenance = make(map[string]model.Provenance)
+	for _, field := range update.PredictedFields() {
+		update.Provenance[field] = model.Provenance{
+			Source:     model.SourceManual,
+			Confidence: &manual,
+			UpdatedAt:  time.Now(),
+		}
+	}
+
+	updatedPerson, err := s.storage.UpdatePerson(ctx, id, update)
 	if err != nil {
 		switch {
 		case errors.Is(err, storage.ErrNoUpdatedFields):
@@ -159,9 +291,50 @@ func (s *Service) Update(
 		}
 	}
 
+	s.recordCorrections(ctx, log, updatedPerson.Name, previous, update)
+
 	return dto.ToPersonResponse(updatedPerson), nil
 }
 
+// recordCorrections feeds manual edits of gender and nationality into name overrides,
+// so the next person with the same name gets the corrected value. Age is specific to
+// the person and an edit that repeats the current value corrects nothing.
+func (s *Service) recordCorrections(
+	ctx context.Context,
+	log *slog.Logger,
+	name string,
+	previous, update *model.Person,
+) {
+	edits := []struct {
+		field, value, current string
+	}{
+		{field: model.FieldGender, value: update.Gender, current: previous.Gender},
+		{field: model.FieldNationality, value: update.Nationality, current: previous.Nationality},
+	}
+
+	for _, e := range edits {
+		if e.value == "" {
+			continue
+		}
+
+		log := log.With(slog.String("field", e.field))
+
+		value, err := overrideService.NormalizeValue(e.field, e.value)
+		if err != nil {
+			log.Info("edit is not a valid correction", sl.Err(err))
+			continue
+		}
+
+		if strings.EqualFold(value, e.current) {
+			continue
+		}
+
+		if err := s.storage.RecordCorrection(ctx, override.Normalize(name), e.field, value); err != nil {
+			log.Error("failed to record correction", sl.Err(err))
+		}
+	}
+}
+
 func (s *Service) People(ctx context.Context,
 	filters *dto.PeopleFilters,
 	pagination *dto.Pagination,
@@ -187,6 +360,31 @@ func (s *Service) People(ctx context.Context,
 	return dto.PeopleToPersonResponse(people), nil
 }
 
+// Person returns a single person with provenance and related links
+func (s *Service) Person(ctx context.Context, id int64) (*dto.PersonDetailsResponse, error) {
+	const op = "service.person.Person"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	log.Info("fetching person")
+
+	person, err := s.storage.PersonByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrPersonNotFound) {
+			log.Info("person not found")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrPersonNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return dto.ToPersonDetailsResponse(person), nil
+}
+
 func (s *Service) Delete(ctx context.Context, id int64) error {
 	const op = "service.person.Delete"
 
@@ -211,3 +409,218 @@ func (s *Service) Delete(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+// applyPredictions fills person attributes from predictions.
+// Attributes below the thresholds are flagged and stored as unknown,
+// or rejected with ErrLowConfidence depending on the policy.
+func (s *Service) applyPredictions(person *model.Person, predictions *model.Predictions) error {
+	p := s.flagged(predictions)
+
+	if p.LowConfidence() && s.cfg.LowConfidencePolicy == config.LowConfidenceReject {
+		var attrs []string
+		if p.Age.LowConfidence {
+			attrs = append(attrs, "age")
+		}
+		if p.Gender.LowConfidence {
+			attrs = append(attrs, "gender")
+		}
+		if p.Nationality.LowConfidence {
+			attrs = append(attrs, "nationality")
+		}
+
+		return fmt.Errorf("%w: %s", ErrLowConfidence, strings.Join(attrs, ", "))
+	}
+
+	person.Age = p.Age.Age
+	if p.Age.LowConfidence {
+		person.Age = 0
+	}
+
+	person.Gender = p.Gender.Gender
+	if p.Gender.LowConfidence {
+		person.Gender = model.Unknown
+	}
+
+	person.Nationality = p.Nationality.Top().CountryID
+	if p.Nationality.LowConfidence {
+		person.Nationality = model.Unknown
+	}
+
+	person.Predictions = &p
+	person.Provenance = provenanceOf(&p, time.Now())
+
+	return nil
+}
+
+// flagged returns a copy of predictions with attributes below the thresholds flagged
+func (s *Service) flagged(predictions *model.Predictions) model.Predictions {
+	p := *predictions
+
+	p.Age.LowConfidence = below(s.cfg.Age, p.Age.Source, 1, p.Age.Count)
+	p.Gender.LowConfidence = below(s.cfg.Gender, p.Gender.Source, p.Gender.Probability, p.Gender.Count)
+	p.Nationality.LowConfidence = below(s.cfg.Nationality,
+		p.Nationality.Source, p.Nationality.Top().Probability, p.Nationality.Count)
+
+	return p
+}
+
+// provenanceOf attributes each predicted field to the provider that answered
+func provenanceOf(p *model.Predictions, at time.Time) map[string]model.Provenance {
+	top := p.Nationality.Top().Probability
+
+	return map[string]model.Provenance{
+		model.FieldAge: {
+			Source:    p.Age.Source,
+			UpdatedAt: at,
+		},
+		model.FieldGender: {
+			Source:     p.Gender.Source,
+			Confidence: &p.Gender.Probability,
+			UpdatedAt:  at,
+		},
+		model.FieldNationality: {
+			Source:     p.Nationality.Source,
+			Confidence: &top,
+			UpdatedAt:  at,
+		},
+	}
+}
+
+// below reports whether a prediction misses the threshold, operator overrides never do.
+// Rules have no sample to count, so only their probability is checked.
+func below(threshold config.ThresholdConfig, source string, probability float64, count int) bool {
+	switch source {
+	case model.SourceOverride:
+		return false
+	case model.SourceRules:
+		return probability < threshold.MinProbability
+	}
+
+	return probability < threshold.MinProbability || count < threshold.MinCount
+}
+
+// enrich queries all providers concurrently, the first failure cancels the rest.
+// In localized mode without a country hint nationality is resolved first
+// and age and gender are predicted for the most probable country.
+func (s *Service) enrich(ctx context.Context, query model.NameQuery) (*model.Predictions, error) {
+	var predictions model.Predictions
+
+	localize := s.cfg.Localize && query.CountryID == ""
+	if localize {
+		var err error
+		if predictions.Nationality, err = s.nationalityProvider.Nationality(ctx, query); err != nil {
+			return nil, fmt.Errorf("nationality provider: %w", err)
+		}
+
+		query.CountryID = predictions.Nationality.Top().CountryID
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		var err error
+		if predictions.Age, err = s.ageProvider.Age(ctx, query); err != nil {
+			return fmt.Errorf("age provider: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		var err error
+		if predictions.Gender, err = s.genderProvider.Gender(ctx, query); err != nil {
+			return fmt.Errorf("gender provider: %w", err)
+		}
+		return nil
+	})
+
+	if !localize {
+		g.Go(func() error {
+			var err error
+			if predictions.Nationality, err = s.nationalityProvider.Nationality(ctx, query); err != nil {
+				return fmt.Errorf("nationality provider: %w", err)
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return &predictions, nil
+}
+
+// enrichBatch resolves predictions for distinct queries, queries any provider can't predict are omitted
+func (s *Service) enrichBatch(
+	ctx context.Context,
+	queries []model.NameQuery,
+) (map[model.NameQuery]*model.Predictions, error) {
+	var (
+		ages          []model.AgePrediction
+		genders       []model.GenderPrediction
+		nationalities []model.NationalityPrediction
+	)
+
+	localized := queries
+	if s.cfg.Localize {
+		var err error
+		if nationalities, err = personClient.Nationalities(ctx, s.nationalityProvider, queries); err != nil {
+			return nil, fmt.Errorf("nationality provider: %w", err)
+		}
+
+		localized = make([]model.NameQuery, len(queries))
+		for i, q := range queries {
+			if q.CountryID == "" {
+				q.CountryID = nationalities[i].Top().CountryID
+			}
+			localized[i] = q
+		}
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		var err error
+		if ages, err = personClient.Ages(ctx, s.ageProvider, localized); err != nil {
+			return fmt.Errorf("age provider: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		var err error
+		if genders, err = personClient.Genders(ctx, s.genderProvider, localized); err != nil {
+			return fmt.Errorf("gender provider: %w", err)
+		}
+		return nil
+	})
+
+	if !s.cfg.Localize {
+		g.Go(func() error {
+			var err error
+			if nationalities, err = personClient.Nationalities(ctx, s.nationalityProvider, queries); err != nil {
+				return fmt.Errorf("nationality provider: %w", err)
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	predictions := make(map[model.NameQuery]*model.Predictions, len(queries))
+	for i, query := range queries {
+		if ages[i].Empty() || genders[i].Empty() || nationalities[i].Empty() {
+			continue
+		}
+
+		predictions[query] = &model.Predictions{
+			Age:         ages[i],
+			Gender:      genders[i],
+			Nationality: nationalities[i],
+		}
+	}
+
+	return predictions, nil
+}
@@ -0,0 +1,325 @@
+package person
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"person-info/internal/config"
+	"person-info/internal/domain/model"
+	"person-info/internal/transport/dto"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+type correction struct {
+	name, field, value string
+}
+
+// stubStorage implements only what a test needs, other methods panic on the nil interface
+type stubStorage struct {
+	Storage
+	mu          sync.Mutex
+	people      map[int64]*model.Person
+	enriched    []*model.Person
+	failed      []int64
+	corrections []correction
+	saved       []*model.Person
+	saveErr     map[string]error
+	enrichErr   map[int64]error
+}
+
+func (s *stubStorage) PersonExists(context.Context, *model.Person) (bool, error) {
+	return false, nil
+}
+
+func (s *stubStorage) SavePerson(_ context.Context, person *model.Person) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.saveErr[person.Name]; err != nil {
+		return err
+	}
+
+	s.saved = append(s.saved, person)
+	person.ID = int64(len(s.saved))
+
+	if s.people != nil {
+		stored := *person
+		s.people[person.ID] = &stored
+	}
+
+	return nil
+}
+
+func (s *stubStorage) PersonByID(_ context.Context, id int64) (*model.Person, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	person := *s.people[id]
+	return &person, nil
+}
+
+func (s *stubStorage) PendingPeople(context.Context) ([]*model.Person, error) {
+	var pending []*model.Person
+	for _, person := range s.people {
+		pending = append(pending, person)
+	}
+
+	return pending, nil
+}
+
+func (s *stubStorage) People(context.Context,
+	*model.PeopleFilters,
+	*model.Pagination,
+	*model.SortOptions,
+) ([]*model.Person, error) {
+	return s.PendingPeople(context.Background())
+}
+
+func (s *stubStorage) SaveEnrichment(_ context.Context, person *model.Person) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.enrichErr[person.ID]; err != nil {
+		return err
+	}
+
+	s.enriched = append(s.enriched, person)
+	return nil
+}
+
+func (s *stubStorage) SaveEnrichmentFailure(_ context.Context, id int64, _, _ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failed = append(s.failed, id)
+	return nil
+}
+
+func (s *stubStorage) RecordCorrection(_ context.Context, name, field, value string) error {
+	s.corrections = append(s.corrections, correction{name: name, field: field, value: value})
+	return nil
+}
+
+func TestRecordCorrections(t *testing.T) {
+	previous := &model.Person{Name: "Alexey", Age: 40, Gender: "male", Nationality: "UA"}
+
+	tests := []struct {
+		name   string
+		update *model.Person
+		want   []correction
+	}{
+		{
+			name:   "age is never a name correction",
+			update: &model.Person{Age: 25},
+		},
+		{
+			name:   "values are normalized",
+			update: &model.Person{Gender: "Female", Nationality: " ru "},
+			want: []correction{
+				{name: "alexey", field: model.FieldGender, value: "female"},
+				{name: "alexey", field: model.FieldNationality, value: "RU"},
+			},
+		},
+		{
+			name:   "unchanged value is not a correction",
+			update: &model.Person{Gender: "Male", Nationality: "ua"},
+		},
+		{
+			name:   "invalid values are skipped",
+			update: &model.Person{Gender: "unknown", Nationality: "Russia"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &stubStorage{}
+			s := &Service{log: discard, storage: storage}
+
+			s.recordCorrections(context.Background(), discard, previous.Name, previous, tt.update)
+
+			assert.Equal(t, tt.want, storage.corrections)
+		})
+	}
+}
+
+type stubProviders struct{}
+
+func (stubProviders) Age(context.Context, model.NameQuery) (model.AgePrediction, error) {
+	return model.AgePrediction{Age: 52, Count: 1000}, nil
+}
+
+func (stubProviders) Gender(context.Context, model.NameQuery) (model.GenderPrediction, error) {
+	return model.GenderPrediction{Gender: "male", Probability: 0.99, Count: 1000}, nil
+}
+
+func (stubProviders) Nationality(context.Context, model.NameQuery) (model.NationalityPrediction, error) {
+	return model.NationalityPrediction{
+		Countries: []model.CountryPrediction{{CountryID: "UA", Probability: 0.6}},
+		Count:     1000,
+	}, nil
+}
+
+func TestEnrichPersonKeepsManualFields(t *testing.T) {
+	manual := 1.0
+	storage := &stubStorage{people: map[int64]*model.Person{
+		7: {
+			ID:          7,
+			Name:        "Sasha",
+			Gender:      "female",
+			Nationality: "RU",
+			Predictions: &model.Predictions{
+				Gender: model.GenderPrediction{Gender: "female", Probability: 1, Count: 1},
+				Nationality: model.NationalityPrediction{
+					Countries: []model.CountryPrediction{{CountryID: "RU", Probability: 1}},
+					Count:     1,
+				},
+			},
+			Provenance: map[string]model.Provenance{
+				model.FieldGender:      {Source: model.SourceManual, Confidence: &manual},
+				model.FieldNationality: {Source: model.SourceManual, Confidence: &manual},
+			},
+		},
+	}}
+
+	s := New(discard, storage, stubProviders{}, stubProviders{}, stubProviders{}, config.EnrichmentConfig{})
+
+	require.NoError(t, s.enrichPerson(context.Background(), enrichmentJob{id: 7}))
+	require.Len(t, storage.enriched, 1)
+
+	person := storage.enriched[0]
+	assert.Equal(t, "female", person.Gender)
+	assert.Equal(t, "RU", person.Nationality)
+	assert.Equal(t, model.SourceManual, person.Provenance[model.FieldGender].Source)
+	assert.Equal(t, model.SourceManual, person.Provenance[model.FieldNationality].Source)
+
+	// stored details of manual fields must not come from the new answers
+	require.NotNil(t, person.Predictions)
+	assert.Equal(t, 1.0, person.Predictions.Gender.Probability)
+	assert.Equal(t, 1, person.Predictions.Gender.Count)
+	assert.Equal(t, []model.CountryPrediction{{CountryID: "RU", Probability: 1}}, person.Predictions.Nationality.Countries)
+	assert.Equal(t, 1, person.Predictions.Nationality.Count)
+
+	assert.Equal(t, 52, person.Age)
+	assert.Equal(t, 1000, person.Predictions.Age.Count)
+	assert.NotEqual(t, model.SourceManual, person.Provenance[model.FieldAge].Source)
+}
+
+func TestStartFeedsBacklogLargerThanQueue(t *testing.T) {
+	storage := &stubStorage{people: make(map[int64]*model.Person)}
+	for id := range int64(20) {
+		storage.people[id] = &model.Person{ID: id, Name: "Anna"}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := New(discard, storage, stubProviders{}, stubProviders{}, stubProviders{}, config.EnrichmentConfig{
+		Workers:   2,
+		QueueSize: 3,
+	})
+	require.NoError(t, s.Start(ctx))
+
+	assert.Eventually(t, func() bool {
+		storage.mu.Lock()
+		defer storage.mu.Unlock()
+
+		return len(storage.enriched) == len(storage.people)
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	s.Wait()
+
+	assert.Empty(t, storage.failed)
+}
+
+func TestSaveBatchReportsSaveFailuresPerPerson(t *testing.T) {
+	errDB := errors.New("connection reset")
+	storage := &stubStorage{saveErr: map[string]error{"Boris": errDB}}
+
+	s := New(discard, storage, stubProviders{}, stubProviders{}, stubProviders{}, config.EnrichmentConfig{})
+
+	results, err := s.SaveBatch(context.Background(), []*dto.CreatePersonRequest{
+		{Name: "Anna", Surname: "Petrova"},
+		{Name: "Boris", Surname: "Petrov"},
+		{Name: "Vera", Surname: "Petrova"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, int64(1), results[0].Person.ID)
+	assert.ErrorIs(t, results[1].Err, errDB)
+	assert.Equal(t, int64(2), results[2].Person.ID)
+}
+
+func TestAcceptOverflowingQueueStaysPending(t *testing.T) {
+	storage := &stubStorage{people: make(map[int64]*model.Person)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := New(discard, storage, stubProviders{}, stubProviders{}, stubProviders{}, config.EnrichmentConfig{
+		Workers:   1,
+		QueueSize: 1,
+	})
+	require.NoError(t, s.Start(ctx))
+
+	const people = 20
+	for i := range people {
+		resp, err := s.Accept(ctx, &dto.CreatePersonRequest{Name: "Anna", Surname: fmt.Sprint("Petrova", i)})
+		require.NoError(t, err)
+		assert.Equal(t, model.EnrichmentPending, resp.EnrichmentStatus)
+	}
+
+	assert.Eventually(t, func() bool {
+		storage.mu.Lock()
+		defer storage.mu.Unlock()
+
+		return len(storage.enriched) == people
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	s.Wait()
+
+	assert.Empty(t, storage.failed)
+}
+
+func TestEnrichPeopleReportsSaveFailuresPerPerson(t *testing.T) {
+	storage := &stubStorage{
+		people: map[int64]*model.Person{
+			1: {ID: 1, Name: "Anna", Provenance: map[string]model.Provenance{}},
+			2: {ID: 2, Name: "Boris", Provenance: map[string]model.Provenance{}},
+			3: {ID: 3, Name: "Vera", Provenance: map[string]model.Provenance{}},
+		},
+		enrichErr: map[int64]error{2: errors.New("connection reset")},
+	}
+
+	s := New(discard, storage, stubProviders{}, stubProviders{}, stubProviders{}, config.EnrichmentConfig{})
+
+	resp, err := s.EnrichPeople(context.Background(), &dto.PeopleFilters{}, &dto.Pagination{}, &dto.SortOptions{}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, resp.Processed)
+	assert.Equal(t, 1, resp.Failed)
+	assert.Equal(t, 2, resp.Changed)
+	assert.Len(t, storage.enriched, 2)
+
+	for _, diff := range resp.People {
+		if diff.ID == 2 {
+			assert.Contains(t, diff.Error, "connection reset")
+			assert.Empty(t, diff.Changes)
+		} else {
+			assert.Empty(t, diff.Error)
+		}
+	}
+}
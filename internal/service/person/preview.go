@@ -0,0 +1,31 @@
+package person
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"person-info/internal/lib/logger/sl"
+	"person-info/internal/transport/dto"
+)
+
+// Preview runs the enrichment pipeline like Save but stores nothing and skips the duplicate check.
+// Attributes below the thresholds are returned flagged instead of rejected.
+func (s *Service) Preview(ctx context.Context, req *dto.PredictionRequest) (*dto.PredictionResponse, error) {
+	const op = "service.person.Preview"
+
+	log := s.log.With(slog.String("op", op))
+
+	log.Info("previewing predictions")
+
+	predictions, err := s.enrich(ctx, dto.PredictionReqToNameQuery(req))
+	if err != nil {
+		log.Error("failed to enrich person", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	p := s.flagged(predictions)
+
+	return dto.ToPredictionResponse(req, &p), nil
+}
@@ -3,6 +3,11 @@ package storage
 import "fmt"
 
 var (
-	ErrPersonNotFound  = fmt.Errorf("person not found")
-	ErrNoUpdatedFields = fmt.Errorf("no updated fields")
+	ErrPersonNotFound     = fmt.Errorf("person not found")
+	ErrNoUpdatedFields    = fmt.Errorf("no updated fields")
+	ErrPredictionNotFound = fmt.Errorf("prediction not found")
+
+	ErrEnrichmentNotFailed = fmt.Errorf("enrichment has not failed")
+	ErrOverrideNotFound    = fmt.Errorf("name override not found")
+	ErrUsageNotFound       = fmt.Errorf("provider usage not found")
 )
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"person-info/internal/domain/model"
+	"person-info/internal/storage"
+)
+
+func (s *Storage) NameOverride(ctx context.Context, name, field string) (*model.NameOverride, error) {
+	const op = "storage.postgres.NameOverride"
+
+	o := model.NameOverride{Name: name, Field: field}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT value, corrections, pinned, updated_at FROM name_overrides
+		WHERE name = $1 AND field = $2
+	`, name, field).Scan(&o.Value, &o.Corrections, &o.Pinned, &o.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrOverrideNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &o, nil
+}
+
+// NameOverrides lists overrides of the name, or all of them if name is empty
+func (s *Storage) NameOverrides(ctx context.Context, name string) ([]*model.NameOverride, error) {
+	const op = "storage.postgres.NameOverrides"
+
+	query := s.builder.
+		Select("name", "field", "value", "corrections", "pinned", "updated_at").
+		From("name_overrides").
+		OrderBy("name", "field")
+
+	if name != "" {
+		query = query.Where("name = ?", name)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var overrides []*model.NameOverride
+	for rows.Next() {
+		var o model.NameOverride
+
+		if err := rows.Scan(&o.Name, &o.Field, &o.Value, &o.Corrections, &o.Pinned, &o.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		overrides = append(overrides, &o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return overrides, nil
+}
+
+// SaveNameOverride pins the override value keeping its correction count
+func (s *Storage) SaveNameOverride(ctx context.Context, name, field, value string) (*model.NameOverride, error) {
+	const op = "storage.postgres.SaveNameOverride"
+
+	o := model.NameOverride{Name: name, Field: field}
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO name_overrides (name, field, value, pinned)
+		VALUES ($1, $2, $3, TRUE)
+		ON CONFLICT (name, field) DO UPDATE SET
+			value = EXCLUDED.value,
+			pinned = TRUE,
+			updated_at = NOW()
+		RETURNING value, corrections, pinned, updated_at
+	`, name, field, value).Scan(&o.Value, &o.Corrections, &o.Pinned, &o.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &o, nil
+}
+
+// RecordCorrection counts a manual edit of a person towards the override value.
+// A different value starts counting over, pinned overrides are left as they are.
+func (s *Storage) RecordCorrection(ctx context.Context, name, field, value string) error {
+	const op = "storage.postgres.RecordCorrection"
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO name_overrides (name, field, value, corrections)
+		VALUES ($1, $2, $3, 1)
+		ON CONFLICT (name, field) DO UPDATE SET
+			value = EXCLUDED.value,
+			corrections = CASE
+				WHEN name_overrides.value = EXCLUDED.value THEN name_overrides.corrections + 1
+				ELSE 1
+			END,
+			updated_at = NOW()
+		WHERE NOT name_overrides.pinned
+	`, name, field, value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteNameOverride(ctx context.Context, name, field string) error {
+	const op = "storage.postgres.DeleteNameOverride"
+
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM name_overrides WHERE name = $1 AND field = $2
+	`, name, field)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrOverrideNotFound)
+	}
+
+	return nil
+}
@@ -5,14 +5,38 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 
 	"person-info/internal/domain/model"
 	"person-info/internal/storage"
 )
 
+var personColumns = []string{
+	"id",
+	"name",
+	"surname",
+	"patronymic",
+	"age",
+	"gender",
+	"nationality",
+	"age_count",
+	"gender_probability",
+	"gender_count",
+	"nationality_count",
+	"age_low_confidence",
+	"gender_low_confidence",
+	"nationality_low_confidence",
+	"country_hint",
+	"enrichment_status",
+	"enrichment_attempts",
+	"enrichment_error",
+	"created_at",
+	"updated_at",
+}
+
 type Storage struct {
 	db      *sql.DB
 	builder sq.StatementBuilderType
@@ -58,14 +82,7 @@ func (s *Storage) People(
 ) ([]*model.Person, error) {
 	const op = "service.person.People"
 
-	query := s.builder.Select(
-		"name",
-		"surname",
-		"patronymic",
-		"age",
-		"gender",
-		"nationality",
-	).From("people")
+	query := s.builder.Select(personColumns...).From("people")
 
 	query = setFilters(query, filters)
 
@@ -97,26 +114,62 @@ func (s *Storage) People(
 	}
 	defer rows.Close()
 
-	var people []*model.Person
-	for rows.Next() {
-		var person model.Person
+	people, err := s.scanPeople(ctx, rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-		err := rows.Scan(
-			&person.Name,
-			&person.Surname,
-			&person.Patronymic,
-			&person.Age,
-			&person.Gender,
-			&person.Nationality,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
+	return people, nil
+}
+
+func (s *Storage) PersonByID(ctx context.Context, id int64) (*model.Person, error) {
+	const op = "storage.postgres.PersonByID"
+
+	query, args, err := s.builder.Select(personColumns...).
+		From("people").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var person model.Person
+	if err := scanPerson(s.db.QueryRowContext(ctx, query, args...), &person); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrPersonNotFound)
 		}
 
-		people = append(people, &person)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err := rows.Err(); err != nil {
+	if err := s.loadDetails(ctx, []*model.Person{&person}); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &person, nil
+}
+
+// PendingPeople returns people waiting for enrichment, oldest first
+func (s *Storage) PendingPeople(ctx context.Context) ([]*model.Person, error) {
+	const op = "storage.postgres.PendingPeople"
+
+	query, args, err := s.builder.Select(personColumns...).
+		From("people").
+		Where(sq.Eq{"enrichment_status": model.EnrichmentPending}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	people, err := s.scanPeople(ctx, rows)
+	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -126,27 +179,59 @@ func (s *Storage) People(
 func (s *Storage) SavePerson(ctx context.Context, person *model.Person) error {
 	const op = "storage.postgres.SavePerson"
 
-	stmt, err := s.db.PrepareContext(ctx, `
-		INSERT INTO people (name, surname, patronymic, age, gender, nationality) 
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`)
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
-	defer stmt.Close()
+	defer tx.Rollback()
+
+	predictions := person.Predictions
+	if predictions == nil {
+		predictions = &model.Predictions{}
+	}
 
-	_, err = stmt.ExecContext(ctx,
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO people (
+			name, surname, patronymic, age, gender, nationality,
+			age_count, gender_probability, gender_count, nationality_count,
+			age_low_confidence, gender_low_confidence, nationality_low_confidence,
+			country_hint, enrichment_status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING id, created_at, updated_at
+	`,
 		person.Name,
 		person.Surname,
 		person.Patronymic,
 		person.Age,
 		person.Gender,
 		person.Nationality,
-	)
+		predictions.Age.Count,
+		predictions.Gender.Probability,
+		predictions.Gender.Count,
+		predictions.Nationality.Count,
+		predictions.Age.LowConfidence,
+		predictions.Gender.LowConfidence,
+		predictions.Nationality.LowConfidence,
+		person.CountryHint,
+		person.EnrichmentStatus,
+	).Scan(&person.ID, &person.CreatedAt, &person.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if err := saveNationalities(ctx, tx, person.ID, predictions.Nationality.Countries); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := saveProvenance(ctx, tx, person.ID, person.Provenance); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	return nil
 }
 
@@ -166,21 +251,23 @@ func (s *Storage) UpdatePerson(
 	}
 
 	query, args, err := updateBuilder.
+		Set("updated_at", sq.Expr("NOW()")).
 		Where(sq.Eq{"id": id}).
-		Suffix("RETURNING name, surname, patronymic, age, gender, nationality").
+		Suffix("RETURNING " + strings.Join(personColumns, ", ")).
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = s.db.QueryRowContext(ctx, query, args...).Scan(
-		&person.Name,
-		&person.Surname,
-		&person.Patronymic,
-		&person.Age,
-		&person.Gender,
-		&person.Nationality,
-	)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	provenance := person.Provenance
+
+	err = scanPerson(tx.QueryRowContext(ctx, query, args...), person)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("%s: %w", op, storage.ErrPersonNotFound)
@@ -189,9 +276,144 @@ func (s *Storage) UpdatePerson(
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if err := saveProvenance(ctx, tx, id, provenance); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.loadDetails(ctx, []*model.Person{person}); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	return person, nil
 }
 
+// SaveEnrichment stores predicted attributes of the person and marks the enrichment done
+func (s *Storage) SaveEnrichment(ctx context.Context, person *model.Person) error {
+	const op = "storage.postgres.SaveEnrichment"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	predictions := person.Predictions
+	if predictions == nil {
+		predictions = &model.Predictions{}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE people SET
+			age = $2, gender = $3, nationality = $4,
+			age_count = $5, gender_probability = $6, gender_count = $7, nationality_count = $8,
+			age_low_confidence = $9, gender_low_confidence = $10, nationality_low_confidence = $11,
+			enrichment_status = $12, enrichment_attempts = enrichment_attempts + 1, enrichment_error = '',
+			updated_at = NOW()
+		WHERE id = $1
+	`,
+		person.ID,
+		person.Age,
+		person.Gender,
+		person.Nationality,
+		predictions.Age.Count,
+		predictions.Gender.Probability,
+		predictions.Gender.Count,
+		predictions.Nationality.Count,
+		predictions.Age.LowConfidence,
+		predictions.Gender.LowConfidence,
+		predictions.Nationality.LowConfidence,
+		model.EnrichmentDone,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrPersonNotFound)
+	}
+
+	if err := saveNationalities(ctx, tx, person.ID, predictions.Nationality.Countries); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := saveProvenance(ctx, tx, person.ID, person.Provenance); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SaveEnrichmentFailure records a failed enrichment attempt,
+// status is pending while the attempt will be retried and failed otherwise
+func (s *Storage) SaveEnrichmentFailure(ctx context.Context, id int64, status, reason string) error {
+	const op = "storage.postgres.SaveEnrichmentFailure"
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE people SET
+			enrichment_status = $2,
+			enrichment_attempts = enrichment_attempts + 1,
+			enrichment_error = $3,
+			updated_at = NOW()
+		WHERE id = $1
+	`, id, status, reason)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrPersonNotFound)
+	}
+
+	return nil
+}
+
+// RetryEnrichment moves a failed enrichment back to pending
+func (s *Storage) RetryEnrichment(ctx context.Context, id int64) (*model.Person, error) {
+	const op = "storage.postgres.RetryEnrichment"
+
+	query, args, err := s.builder.Update("people").
+		Set("enrichment_status", model.EnrichmentPending).
+		Set("enrichment_attempts", 0).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "enrichment_status": model.EnrichmentFailed}).
+		Suffix("RETURNING " + strings.Join(personColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var person model.Person
+	err = scanPerson(s.db.QueryRowContext(ctx, query, args...), &person)
+	if err == nil {
+		return &person, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM people WHERE id = $1)
+	`, id).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrPersonNotFound)
+	}
+
+	return nil, fmt.Errorf("%s: %w", op, storage.ErrEnrichmentNotFailed)
+}
+
 func (s *Storage) DeletePerson(ctx context.Context, id int64) error {
 	const op = "storage.postgres.DeletePerson"
 
@@ -232,6 +454,211 @@ func (s *Storage) Ping(ctx context.Context) error {
 	return nil
 }
 
+func (s *Storage) scanPeople(ctx context.Context, rows *sql.Rows) ([]*model.Person, error) {
+	var people []*model.Person
+	for rows.Next() {
+		var person model.Person
+
+		if err := scanPerson(rows, &person); err != nil {
+			return nil, err
+		}
+
+		people = append(people, &person)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadDetails(ctx, people); err != nil {
+		return nil, err
+	}
+
+	return people, nil
+}
+
+// loadDetails fills nationality distributions and provenance of the people
+func (s *Storage) loadDetails(ctx context.Context, people []*model.Person) error {
+	ids := make([]int64, len(people))
+	for i, person := range people {
+		ids[i] = person.ID
+	}
+
+	countries, err := s.nationalities(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	provenance, err := s.provenance(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, person := range people {
+		person.Predictions.Nationality.Countries = countries[person.ID]
+		person.Provenance = provenance[person.ID]
+	}
+
+	return nil
+}
+
+func (s *Storage) provenance(
+	ctx context.Context,
+	ids []int64,
+) (map[int64]map[string]model.Provenance, error) {
+	provenance := make(map[int64]map[string]model.Provenance, len(ids))
+	if len(ids) == 0 {
+		return provenance, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT person_id, field, source, confidence, updated_at FROM person_provenance
+		WHERE person_id = ANY($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id    int64
+			field string
+			p     model.Provenance
+		)
+
+		if err := rows.Scan(&id, &field, &p.Source, &p.Confidence, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		if provenance[id] == nil {
+			provenance[id] = make(map[string]model.Provenance)
+		}
+		provenance[id][field] = p
+	}
+
+	return provenance, rows.Err()
+}
+
+func (s *Storage) nationalities(
+	ctx context.Context,
+	ids []int64,
+) (map[int64][]model.CountryPrediction, error) {
+	countries := make(map[int64][]model.CountryPrediction, len(ids))
+	if len(ids) == 0 {
+		return countries, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT person_id, country_id, probability FROM person_nationalities
+		WHERE person_id = ANY($1)
+		ORDER BY person_id, rank
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id      int64
+			country model.CountryPrediction
+		)
+
+		if err := rows.Scan(&id, &country.CountryID, &country.Probability); err != nil {
+			return nil, err
+		}
+
+		countries[id] = append(countries[id], country)
+	}
+
+	return countries, rows.Err()
+}
+
+func saveNationalities(
+	ctx context.Context,
+	tx *sql.Tx,
+	id int64,
+	countries []model.CountryPrediction,
+) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM person_nationalities WHERE person_id = $1`, id); err != nil {
+		return err
+	}
+
+	for rank, country := range countries {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO person_nationalities (person_id, rank, country_id, probability)
+			VALUES ($1, $2, $3, $4)
+		`, id, rank+1, country.CountryID, country.Probability)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func saveProvenance(
+	ctx context.Context,
+	tx *sql.Tx,
+	id int64,
+	provenance map[string]model.Provenance,
+) error {
+	for field, p := range provenance {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO person_provenance (person_id, field, source, confidence, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (person_id, field) DO UPDATE SET
+				source = EXCLUDED.source,
+				confidence = EXCLUDED.confidence,
+				updated_at = EXCLUDED.updated_at
+		`, id, field, p.Source, p.Confidence, p.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPerson(row rowScanner, person *model.Person) error {
+	var predictions model.Predictions
+
+	err := row.Scan(
+		&person.ID,
+		&person.Name,
+		&person.Surname,
+		&person.Patronymic,
+		&person.Age,
+		&person.Gender,
+		&person.Nationality,
+		&predictions.Age.Count,
+		&predictions.Gender.Probability,
+		&predictions.Gender.Count,
+		&predictions.Nationality.Count,
+		&predictions.Age.LowConfidence,
+		&predictions.Gender.LowConfidence,
+		&predictions.Nationality.LowConfidence,
+		&person.CountryHint,
+		&person.EnrichmentStatus,
+		&person.EnrichmentAttempts,
+		&person.EnrichmentError,
+		&person.CreatedAt,
+		&person.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	person.Predictions = &predictions
+
+	return nil
+}
+
 func setFilters(query sq.SelectBuilder, filters *model.PeopleFilters) sq.SelectBuilder {
 	if filters.Name != "" {
 		query = query.Where(sq.ILike{"name": fmt.Sprintf("%%%s%%", filters.Name)})
@@ -253,6 +680,20 @@ func setFilters(query sq.SelectBuilder, filters *model.PeopleFilters) sq.SelectB
 		query = query.Where(sq.Eq{"nationality": filters.Nationality})
 	}
 
+	if filters.EnrichmentStatus != "" {
+		query = query.Where(sq.Eq{"enrichment_status": filters.EnrichmentStatus})
+	}
+
+	if filters.LowConfidence != nil {
+		const lowConfidence = "(age_low_confidence OR gender_low_confidence OR nationality_low_confidence)"
+
+		if *filters.LowConfidence {
+			query = query.Where(lowConfidence)
+		} else {
+			query = query.Where("NOT " + lowConfidence)
+		}
+	}
+
 	return query
 }
 
@@ -270,15 +711,21 @@ func setUpdatedFields(updateBuilder sq.UpdateBuilder, person *model.Person) sq.U
 	}
 
 	if person.Age > 0 {
-		updateBuilder = updateBuilder.Set("age", person.Age)
+		updateBuilder = updateBuilder.
+			Set("age", person.Age).
+			Set("age_low_confidence", false)
 	}
 
 	if person.Gender != "" {
-		updateBuilder = updateBuilder.Set("gender", person.Gender)
+		updateBuilder = updateBuilder.
+			Set("gender", person.Gender).
+			Set("gender_low_confidence", false)
 	}
 
 	if person.Nationality != "" {
-		updateBuilder = updateBuilder.Set("nationality", person.Nationality)
+		updateBuilder = updateBuilder.
+			Set("nationality", person.Nationality).
+			Set("nationality_low_confidence", false)
 	}
 
 	return updateBuilder
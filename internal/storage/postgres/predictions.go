@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"person-info/internal/storage"
+)
+
+func (s *Storage) Prediction(
+	ctx context.Context,
+	name, countryID, kind string,
+	ttl time.Duration,
+) ([]byte, error) {
+	const op = "storage.postgres.Prediction"
+
+	var payload []byte
+	err := s.db.QueryRowContext(ctx, `
+		SELECT payload FROM name_predictions
+		WHERE name = $1 AND country_id = $2 AND kind = $3 AND fetched_at > $4
+	`, name, countryID, kind, time.Now().Add(-ttl)).Scan(&payload)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrPredictionNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return payload, nil
+}
+
+func (s *Storage) Predictions(
+	ctx context.Context,
+	names []string,
+	countryID, kind string,
+	ttl time.Duration,
+) (map[string][]byte, error) {
+	const op = "storage.postgres.Predictions"
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT name, payload FROM name_predictions
+		WHERE name = ANY($1) AND country_id = $2 AND kind = $3 AND fetched_at > $4
+	`, pq.Array(names), countryID, kind, time.Now().Add(-ttl))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	payloads := make(map[string][]byte, len(names))
+	for rows.Next() {
+		var (
+			name    string
+			payload []byte
+		)
+
+		if err := rows.Scan(&name, &payload); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		payloads[name] = payload
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return payloads, nil
+}
+
+func (s *Storage) SavePrediction(ctx context.Context, name, countryID, kind string, payload []byte) error {
+	const op = "storage.postgres.SavePrediction"
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO name_predictions (name, country_id, kind, payload, fetched_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (name, kind, country_id) DO UPDATE
+		SET payload = EXCLUDED.payload, fetched_at = EXCLUDED.fetched_at
+	`, name, countryID, kind, payload)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) DeletePredictions(ctx context.Context, name string) (int64, error) {
+	const op = "storage.postgres.DeletePredictions"
+
+	query := s.builder.Delete("name_predictions")
+	if name != "" {
+		query = query.Where("name = ?", name)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result, err := s.db.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, _ := result.RowsAffected()
+
+	return n, nil
+}
+
+func (s *Storage) PredictionsCount(ctx context.Context) (int64, error) {
+	const op = "storage.postgres.PredictionsCount"
+
+	var count int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM name_predictions`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
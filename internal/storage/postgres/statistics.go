@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"person-info/internal/domain/model"
+)
+
+// RefreshNameStatistics rebuilds per-name value counts from stored people.
+// Low confidence values are skipped unless set manually, and so are values
+// learned from the statistics themselves, so they don't reinforce each other.
+func (s *Storage) RefreshNameStatistics(ctx context.Context) (int64, error) {
+	const op = "storage.postgres.RefreshNameStatistics"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM name_statistics`); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		WITH known AS (
+			SELECT p.id, LOWER(TRIM(p.name)) AS name, f.field, f.value, f.low_confidence,
+				pp.source
+			FROM people p
+			CROSS JOIN LATERAL (VALUES
+				('age', CASE WHEN p.age > 0 THEN p.age::TEXT END, p.age_low_confidence),
+				('gender', NULLIF(p.gender, $1), p.gender_low_confidence),
+				('nationality', NULLIF(p.nationality, $1), p.nationality_low_confidence)
+			) AS f (field, value, low_confidence)
+			LEFT JOIN person_provenance pp ON pp.person_id = p.id AND pp.field = f.field
+			WHERE p.enrichment_status = $2 AND f.value IS NOT NULL AND f.value <> ''
+		)
+		INSERT INTO name_statistics (name, field, value, people)
+		SELECT name, field, value, COUNT(*)
+		FROM known
+		WHERE (NOT low_confidence OR source = $3) AND source IS DISTINCT FROM $4
+		GROUP BY name, field, value
+	`, model.Unknown, model.EnrichmentDone, model.SourceManual, model.SourceLearned)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, _ := result.RowsAffected()
+
+	return n, nil
+}
+
+// NameStatistics returns how many people with the name have each value of the field
+func (s *Storage) NameStatistics(ctx context.Context, name, field string) (map[string]int, error) {
+	const op = "storage.postgres.NameStatistics"
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT value, people FROM name_statistics
+		WHERE name = $1 AND field = $2
+	`, name, field)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			value  string
+			people int
+		)
+
+		if err := rows.Scan(&value, &people); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		counts[value] = people
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"person-info/internal/domain/model"
+	"person-info/internal/storage"
+)
+
+func (s *Storage) ProviderUsage(ctx context.Context, provider string, day time.Time) (*model.ProviderUsage, error) {
+	const op = "storage.postgres.ProviderUsage"
+
+	u := model.ProviderUsage{Provider: provider, Day: day}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT calls, names, quota_limit, remaining, reset_at, updated_at FROM provider_usage
+		WHERE provider = $1 AND day = $2
+	`, provider, day).Scan(&u.Calls, &u.Names, &u.Limit, &u.Remaining, &u.ResetAt, &u.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrUsageNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &u, nil
+}
+
+// AddProviderUsage adds calls and names of delta to the day's counters,
+// limit and rate limit headers of delta replace the stored ones when set
+func (s *Storage) AddProviderUsage(ctx context.Context, delta *model.ProviderUsage) (*model.ProviderUsage, error) {
+	const op = "storage.postgres.AddProviderUsage"
+
+	u := model.ProviderUsage{Provider: delta.Provider, Day: delta.Day}
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO provider_usage (provider, day, calls, names, quota_limit, remaining, reset_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (provider, day) DO UPDATE SET
+			calls = provider_usage.calls + EXCLUDED.calls,
+			names = provider_usage.names + EXCLUDED.names,
+			quota_limit = EXCLUDED.quota_limit,
+			remaining = COALESCE(EXCLUDED.remaining, provider_usage.remaining),
+			reset_at = COALESCE(EXCLUDED.reset_at, provider_usage.reset_at),
+			updated_at = NOW()
+		RETURNING calls, names, quota_limit, remaining, reset_at, updated_at
+	`, delta.Provider, delta.Day, delta.Calls, delta.Names, delta.Limit, delta.Remaining, delta.ResetAt,
+	).Scan(&u.Calls, &u.Names, &u.Limit, &u.Remaining, &u.ResetAt, &u.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &u, nil
+}
@@ -1,11 +1,29 @@
 package dto
 
-import "person-info/internal/domain/model"
+import (
+	"strings"
+
+	"person-info/internal/domain/model"
+)
 
 type CreatePersonRequest struct {
 	Name       string `json:"name" binding:"required" example:"John"`
 	Surname    string `json:"surname" binding:"required" example:"Snow"`
 	Patronymic string `json:"patronymic,omitempty" example:"Dmitrievich"`
+	// CountryHint localizes age and gender predictions, ISO 3166-1 alpha-2
+	CountryHint string `json:"country_hint,omitempty" binding:"omitempty,iso3166_1_alpha2" example:"RU"`
+}
+
+type PredictionRequest struct {
+	Name       string `form:"name" binding:"required" example:"John"`
+	Surname    string `form:"surname" example:"Snow"`
+	Patronymic string `form:"patronymic" example:"Dmitrievich"`
+	// CountryHint localizes age and gender predictions, ISO 3166-1 alpha-2
+	CountryHint string `form:"country_hint" binding:"omitempty,iso3166_1_alpha2" example:"RU"`
+}
+
+type CreatePeopleRequest struct {
+	People []*CreatePersonRequest `json:"people" binding:"required,min=1,max=100,dive"`
 }
 
 type UpdatePersonRequest struct {
@@ -24,6 +42,22 @@ type PeopleFilters struct {
 	Age         int    `form:"age,omitempty" binding:"numeric" validate:"omitempty,min=1,max=100" example:"30"`
 	Gender      string `form:"gender,omitempty" validate:"omitempty,oneof=male female" example:"male"`
 	Nationality string `form:"nationality,omitempty" example:"RU"`
+	// LowConfidence selects people with or without attributes predicted below the thresholds
+	LowConfidence    *bool  `form:"low_confidence,omitempty" example:"true"`
+	EnrichmentStatus string `form:"enrichment_status,omitempty" validate:"omitempty,oneof=pending done failed" example:"failed"`
+}
+
+type SaveOverrideRequest struct {
+	Value string `json:"value" binding:"required" example:"female"`
+}
+
+type OverridesFilter struct {
+	Name string `form:"name" example:"Sasha"`
+}
+
+type EnrichOptions struct {
+	// Force overwrites attributes set by hand
+	Force bool `form:"force" example:"false"`
 }
 
 type Pagination struct {
@@ -49,20 +83,41 @@ func UpdateReqToPersonModel(p *UpdatePersonRequest) *model.Person {
 
 func CreateReqToPersonModel(p *CreatePersonRequest) *model.Person {
 	return &model.Person{
+		Name:        p.Name,
+		Surname:     p.Surname,
+		Patronymic:  p.Patronymic,
+		CountryHint: strings.ToUpper(p.CountryHint),
+	}
+}
+
+func CreateReqToNameQuery(p *CreatePersonRequest) model.NameQuery {
+	return model.NameQuery{
+		Name:       p.Name,
+		Surname:    p.Surname,
+		Patronymic: p.Patronymic,
+		CountryID:  strings.ToUpper(p.CountryHint),
+	}
+}
+
+func PredictionReqToNameQuery(p *PredictionRequest) model.NameQuery {
+	return model.NameQuery{
 		Name:       p.Name,
 		Surname:    p.Surname,
 		Patronymic: p.Patronymic,
+		CountryID:  strings.ToUpper(p.CountryHint),
 	}
 }
 
 func ToPeopleFiltersModel(p *PeopleFilters) *model.PeopleFilters {
 	return &model.PeopleFilters{
-		Name:        p.Name,
-		Surname:     p.Surname,
-		Patronymic:  p.Patronymic,
-		Age:         p.Age,
-		Gender:      p.Gender,
-		Nationality: p.Nationality,
+		Name:             p.Name,
+		Surname:          p.Surname,
+		Patronymic:       p.Patronymic,
+		Age:              p.Age,
+		Gender:           p.Gender,
+		Nationality:      p.Nationality,
+		LowConfidence:    p.LowConfidence,
+		EnrichmentStatus: p.EnrichmentStatus,
 	}
 }
 
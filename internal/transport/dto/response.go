@@ -1,28 +1,276 @@
 package dto
 
-import "person-info/internal/domain/model"
+import (
+	"fmt"
+	"time"
+
+	"person-info/internal/domain/model"
+)
 
 type ErrorResponse struct {
 	Error string `json:"error" example:"Something went wrong"`
 }
 
 type PersonResponse struct {
+	ID          int64  `json:"id" example:"42"`
 	Name        string `json:"name" example:"Matvey"`
 	Surname     string `json:"surname" example:"Likhanov"`
 	Patronymic  string `json:"patronymic" example:"Dmitrievich"`
 	Age         int    `json:"age" example:"20"`
 	Gender      string `json:"gender" example:"Male"`
 	Nationality string `json:"nationality" example:"RU"`
+	// LowConfidence is set when any attribute was predicted below the thresholds and stored as unknown
+	LowConfidence bool `json:"low_confidence" example:"false"`
+	// EnrichmentStatus is pending until background enrichment is done or failed
+	EnrichmentStatus string `json:"enrichment_status" example:"done"`
+	EnrichmentError  string `json:"enrichment_error,omitempty" example:"age provider: invalid name"`
+
+	CreatedAt time.Time `json:"created_at" example:"2025-01-01T12:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2025-01-01T12:00:00Z"`
+
+	Predictions *PredictionsResponse `json:"predictions,omitempty"`
+}
+
+// PersonDetailsResponse is a single person with the provenance of each predicted
+// attribute and links to related resources, details the people list leaves out
+type PersonDetailsResponse struct {
+	PersonResponse
+	Provenance map[string]FieldProvenanceResponse `json:"provenance"`
+	Links      PersonLinksResponse                `json:"links"`
+}
+
+type PersonLinksResponse struct {
+	Self       string `json:"self" example:"/people/42"`
+	Provenance string `json:"provenance" example:"/people/42/provenance"`
+	Enrich     string `json:"enrich" example:"/people/42/enrich"`
+	// Retry is set while the background enrichment has failed
+	Retry string `json:"retry,omitempty" example:"/people/42/retry"`
+}
+
+// AcceptedResponse is returned for a person whose enrichment runs in the background
+type AcceptedResponse struct {
+	ID               int64  `json:"id" example:"42"`
+	EnrichmentStatus string `json:"enrichment_status" example:"pending"`
+}
+
+// EnrichmentDiffResponse reports attributes changed by re-enrichment of a person
+type EnrichmentDiffResponse struct {
+	ID      int64                 `json:"id" example:"42"`
+	Changes []FieldChangeResponse `json:"changes"`
+	// Kept lists attributes edited by hand that were left alone
+	Kept  []string `json:"kept,omitempty" example:"gender"`
+	Error string   `json:"error,omitempty" example:"invalid name"`
+}
+
+type FieldChangeResponse struct {
+	Field string `json:"field" example:"age"`
+	Old   any    `json:"old" swaggertype:"string" example:"41"`
+	New   any    `json:"new" swaggertype:"string" example:"43"`
+}
+
+type BulkEnrichmentResponse struct {
+	Processed int                       `json:"processed" example:"10"`
+	Changed   int                       `json:"changed" example:"3"`
+	Failed    int                       `json:"failed" example:"1"`
+	People    []*EnrichmentDiffResponse `json:"people"`
+}
+
+type ProvenanceResponse struct {
+	ID     int64                              `json:"id" example:"42"`
+	Fields map[string]FieldProvenanceResponse `json:"fields"`
+}
+
+type FieldProvenanceResponse struct {
+	Source     string    `json:"source" example:"genderize"`
+	Confidence *float64  `json:"confidence,omitempty" example:"0.98"`
+	UpdatedAt  time.Time `json:"updated_at" example:"2025-01-01T12:00:00Z"`
+}
+
+type NameOverrideResponse struct {
+	Name        string    `json:"name" example:"sasha"`
+	Field       string    `json:"field" example:"gender"`
+	Value       string    `json:"value" example:"female"`
+	Corrections int       `json:"corrections" example:"3"`
+	Pinned      bool      `json:"pinned" example:"false"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2025-01-01T12:00:00Z"`
+}
+
+type BatchPersonResponse struct {
+	Status int             `json:"status" example:"201"`
+	Person *PersonResponse `json:"person,omitempty"`
+	Error  string          `json:"error,omitempty" example:"person already exists"`
+}
+
+// PredictionResponse holds the most probable values, Predictions tells how confident they are
+type PredictionResponse struct {
+	Name        string               `json:"name" example:"John"`
+	Surname     string               `json:"surname,omitempty" example:"Snow"`
+	Patronymic  string               `json:"patronymic,omitempty" example:"Dmitrievich"`
+	Age         int                  `json:"age" example:"34"`
+	Gender      string               `json:"gender" example:"male"`
+	Nationality string               `json:"nationality" example:"RU"`
+	Predictions *PredictionsResponse `json:"predictions"`
+}
+
+type PredictionsResponse struct {
+	Age         AgePredictionResponse         `json:"age"`
+	Gender      GenderPredictionResponse      `json:"gender"`
+	Nationality NationalityPredictionResponse `json:"nationality"`
+}
+
+type AgePredictionResponse struct {
+	Count         int               `json:"count" example:"1247"`
+	Source        string            `json:"source,omitempty" example:"agify"`
+	LowConfidence bool              `json:"low_confidence,omitempty" example:"false"`
+	Ensemble      *EnsembleResponse `json:"ensemble,omitempty"`
+}
+
+type GenderPredictionResponse struct {
+	Probability   float64           `json:"probability" example:"0.98"`
+	Count         int               `json:"count" example:"1247"`
+	Source        string            `json:"source,omitempty" example:"genderize"`
+	LowConfidence bool              `json:"low_confidence,omitempty" example:"false"`
+	Ensemble      *EnsembleResponse `json:"ensemble,omitempty"`
+}
+
+type NationalityPredictionResponse struct {
+	Count         int                         `json:"count" example:"1247"`
+	Countries     []CountryPredictionResponse `json:"countries"`
+	Source        string                      `json:"source,omitempty" example:"nationalize"`
+	LowConfidence bool                        `json:"low_confidence,omitempty" example:"false"`
+	Ensemble      *EnsembleResponse           `json:"ensemble,omitempty"`
+}
+
+type CountryPredictionResponse struct {
+	CountryID   string  `json:"country_id" example:"RU"`
+	Probability float64 `json:"probability" example:"0.43"`
+}
+
+type EnsembleResponse struct {
+	Members      []EnsembleMemberResponse `json:"members"`
+	Disagreement float64                  `json:"disagreement" example:"0.25"`
+}
+
+type EnsembleMemberResponse struct {
+	Provider    string  `json:"provider" example:"genderize"`
+	Weight      float64 `json:"weight" example:"2"`
+	Share       float64 `json:"share" example:"0.67"`
+	Answer      string  `json:"answer,omitempty" example:"female"`
+	Probability float64 `json:"probability,omitempty" example:"0.91"`
+	Error       string  `json:"error,omitempty"`
+}
+
+type HealthResponse struct {
+	Status    string            `json:"status" example:"ok"`
+	Storage   string            `json:"storage" example:"ok"`
+	Providers map[string]string `json:"providers"`
+}
+
+type ProviderUsageResponse struct {
+	Provider     string     `json:"provider" example:"agify"`
+	Day          string     `json:"day" example:"2025-01-01"`
+	Calls        int        `json:"calls" example:"12"`
+	Names        int        `json:"names" example:"57"`
+	Limit        int        `json:"limit" example:"100"`
+	Left         int        `json:"left" example:"43"`
+	ResetAt      *time.Time `json:"reset_at,omitempty" example:"2025-01-02T00:00:00Z"`
+	Degraded     bool       `json:"degraded" example:"false"`
+	CircuitState string     `json:"circuit_state" example:"closed"`
+}
+
+type InvalidateCacheResponse struct {
+	Deleted int64 `json:"deleted" example:"3"`
 }
 
 func ToPersonResponse(p *model.Person) *PersonResponse {
 	return &PersonResponse{
-		Name:        p.Name,
-		Surname:     p.Surname,
-		Patronymic:  p.Patronymic,
-		Age:         p.Age,
-		Gender:      p.Gender,
-		Nationality: p.Nationality,
+		ID:            p.ID,
+		Name:          p.Name,
+		Surname:       p.Surname,
+		Patronymic:    p.Patronymic,
+		Age:           p.Age,
+		Gender:        p.Gender,
+		Nationality:   p.Nationality,
+		LowConfidence: p.Predictions != nil && p.Predictions.LowConfidence(),
+		Predictions:   ToPredictionsResponse(p.Predictions),
+
+		EnrichmentStatus: p.EnrichmentStatus,
+		EnrichmentError:  p.EnrichmentError,
+
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+func ToPredictionResponse(req *PredictionRequest, p *model.Predictions) *PredictionResponse {
+	return &PredictionResponse{
+		Name:        req.Name,
+		Surname:     req.Surname,
+		Patronymic:  req.Patronymic,
+		Age:         p.Age.Age,
+		Gender:      p.Gender.Gender,
+		Nationality: p.Nationality.Top().CountryID,
+		Predictions: ToPredictionsResponse(p),
+	}
+}
+
+func ToPredictionsResponse(p *model.Predictions) *PredictionsResponse {
+	if p == nil {
+		return nil
+	}
+
+	countries := make([]CountryPredictionResponse, len(p.Nationality.Countries))
+	for i, c := range p.Nationality.Countries {
+		countries[i] = CountryPredictionResponse{
+			CountryID:   c.CountryID,
+			Probability: c.Probability,
+		}
+	}
+
+	return &PredictionsResponse{
+		Age: AgePredictionResponse{
+			Count:         p.Age.Count,
+			Source:        p.Age.Source,
+			LowConfidence: p.Age.LowConfidence,
+			Ensemble:      ToEnsembleResponse(p.Age.Ensemble),
+		},
+		Gender: GenderPredictionResponse{
+			Probability:   p.Gender.Probability,
+			Count:         p.Gender.Count,
+			Source:        p.Gender.Source,
+			LowConfidence: p.Gender.LowConfidence,
+			Ensemble:      ToEnsembleResponse(p.Gender.Ensemble),
+		},
+		Nationality: NationalityPredictionResponse{
+			Count:         p.Nationality.Count,
+			Countries:     countries,
+			Source:        p.Nationality.Source,
+			LowConfidence: p.Nationality.LowConfidence,
+			Ensemble:      ToEnsembleResponse(p.Nationality.Ensemble),
+		},
+	}
+}
+
+func ToEnsembleResponse(e *model.Ensemble) *EnsembleResponse {
+	if e == nil {
+		return nil
+	}
+
+	members := make([]EnsembleMemberResponse, len(e.Members))
+	for i, m := range e.Members {
+		members[i] = EnsembleMemberResponse{
+			Provider:    m.Provider,
+			Weight:      m.Weight,
+			Share:       m.Share,
+			Answer:      m.Answer,
+			Probability: m.Probability,
+			Error:       m.Error,
+		}
+	}
+
+	return &EnsembleResponse{
+		Members:      members,
+		Disagreement: e.Disagreement,
 	}
 }
 
@@ -34,3 +282,76 @@ func PeopleToPersonResponse(people []*model.Person) []*PersonResponse {
 
 	return peopleResponse
 }
+
+func ToProvenanceResponse(p *model.Person) *ProvenanceResponse {
+	return &ProvenanceResponse{
+		ID:     p.ID,
+		Fields: toFieldProvenanceResponse(p.Provenance),
+	}
+}
+
+func ToPersonDetailsResponse(p *model.Person) *PersonDetailsResponse {
+	self := fmt.Sprintf("/people/%d", p.ID)
+
+	links := PersonLinksResponse{
+		Self:       self,
+		Provenance: self + "/provenance",
+		Enrich:     self + "/enrich",
+	}
+	if p.EnrichmentStatus == model.EnrichmentFailed {
+		links.Retry = self + "/retry"
+	}
+
+	return &PersonDetailsResponse{
+		PersonResponse: *ToPersonResponse(p),
+		Provenance:     toFieldProvenanceResponse(p.Provenance),
+		Links:          links,
+	}
+}
+
+func toFieldProvenanceResponse(provenance map[string]model.Provenance) map[string]FieldProvenanceResponse {
+	fields := make(map[string]FieldProvenanceResponse, len(provenance))
+	for field, p := range provenance {
+		fields[field] = FieldProvenanceResponse{
+			Source:     p.Source,
+			Confidence: p.Confidence,
+			UpdatedAt:  p.UpdatedAt,
+		}
+	}
+
+	return fields
+}
+
+func ToNameOverrideResponse(o *model.NameOverride) *NameOverrideResponse {
+	return &NameOverrideResponse{
+		Name:        o.Name,
+		Field:       o.Field,
+		Value:       o.Value,
+		Corrections: o.Corrections,
+		Pinned:      o.Pinned,
+		UpdatedAt:   o.UpdatedAt,
+	}
+}
+
+func ToNameOverridesResponse(overrides []*model.NameOverride) []*NameOverrideResponse {
+	resp := make([]*NameOverrideResponse, len(overrides))
+	for i, o := range overrides {
+		resp[i] = ToNameOverrideResponse(o)
+	}
+
+	return resp
+}
+
+func ToProviderUsageResponse(u *model.ProviderUsage, circuitState string) *ProviderUsageResponse {
+	return &ProviderUsageResponse{
+		Provider:     u.Provider,
+		Day:          u.Day.Format(time.DateOnly),
+		Calls:        u.Calls,
+		Names:        u.Names,
+		Limit:        u.Limit,
+		Left:         u.Left(),
+		ResetAt:      u.ResetAt,
+		Degraded:     u.Degraded,
+		CircuitState: circuitState,
+	}
+}
@@ -0,0 +1,55 @@
+package invalidate
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/lib/logger/sl"
+	"person-info/internal/transport/dto"
+)
+
+type CacheInvalidator interface {
+	Invalidate(ctx context.Context, name string) (int64, error)
+}
+
+// @Summary Invalidate prediction cache
+// @Description Removes cached predictions for a name, or the whole cache when name is omitted
+// @Tags /admin
+// @Produce json
+// @Param name path string false "Name"
+// @Success 200 {object} dto.InvalidateCacheResponse "Cache invalidated"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/cache/{name} [delete]
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	cacheInvalidators ...CacheInvalidator,
+) gin.HandlerFunc {
+	const op = "handler.cache.invalidate.New"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		name := c.Param("name")
+
+		log.Debug("invalidate cache", slog.String("name", name))
+
+		var deleted int64
+		for _, invalidator := range cacheInvalidators {
+			n, err := invalidator.Invalidate(ctx, name)
+			if err != nil {
+				log.Error("failed to invalidate cache", sl.Err(err))
+
+				c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"})
+				return
+			}
+
+			deleted += n
+		}
+
+		c.JSON(http.StatusOK, dto.InvalidateCacheResponse{Deleted: deleted})
+	}
+}
@@ -0,0 +1,46 @@
+package stats
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/client/person/cache"
+	"person-info/internal/lib/logger/sl"
+	"person-info/internal/transport/dto"
+)
+
+type StatsProvider interface {
+	Stats(ctx context.Context) (*cache.Stats, error)
+}
+
+// @Summary Get prediction cache stats
+// @Description Returns cached entries count and hit/miss counters per prediction kind
+// @Tags /admin
+// @Produce json
+// @Success 200 {object} cache.Stats "Cache stats"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/cache [get]
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	statsProvider StatsProvider,
+) gin.HandlerFunc {
+	const op = "handler.cache.stats.New"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		stats, err := statsProvider.Stats(ctx)
+		if err != nil {
+			log.Error("failed to get cache stats", sl.Err(err))
+
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, stats)
+	}
+}
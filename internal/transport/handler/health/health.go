@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/lib/logger/sl"
+	"person-info/internal/transport/dto"
+)
+
+const (
+	statusOK       = "ok"
+	statusDegraded = "degraded"
+	statusDown     = "down"
+
+	circuitClosed = "closed"
+)
+
+type StorageHealthChecker interface {
+	Ping(ctx context.Context) error
+}
+
+type CircuitStateProvider interface {
+	CircuitState() string
+}
+
+// @Summary Service health
+// @Description Reports postgres availability and circuit breaker state of each prediction provider
+// @Tags /health
+// @Produce json
+// @Success 200 {object} dto.HealthResponse "Service is healthy or degraded"
+// @Failure 503 {object} dto.HealthResponse "Storage is unavailable"
+// @Router /health [get]
+func New(
+	log *slog.Logger,
+	storage StorageHealthChecker,
+	providers map[string]CircuitStateProvider,
+) gin.HandlerFunc {
+	const op = "handler.health.New"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+		defer cancel()
+
+		resp := dto.HealthResponse{
+			Status:    statusOK,
+			Storage:   statusOK,
+			Providers: make(map[string]string, len(providers)),
+		}
+
+		for name, provider := range providers {
+			state := provider.CircuitState()
+			if state != circuitClosed {
+				resp.Status = statusDegraded
+			}
+
+			resp.Providers[name] = state
+		}
+
+		if err := storage.Ping(ctx); err != nil {
+			log.Error("postgres health check failed", sl.Err(err))
+
+			resp.Status = statusDown
+			resp.Storage = statusDown
+
+			c.JSON(http.StatusServiceUnavailable, resp)
+			return
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
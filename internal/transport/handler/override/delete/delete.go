@@ -0,0 +1,53 @@
+package delete
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/lib/logger/sl"
+	overrideService "person-info/internal/service/override"
+	"person-info/internal/transport/dto"
+)
+
+type OverrideDeleter interface {
+	Delete(ctx context.Context, name, field string) error
+}
+
+// @Summary Delete a name override
+// @Description Removes an override, the name is answered by the providers again
+// @Tags /admin
+// @Param name path string true "Name"
+// @Param field path string true "Field" Enums(age, gender, nationality)
+// @Success 204 "Override deleted"
+// @Failure 404 {object} dto.ErrorResponse "Override not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/overrides/{name}/{field} [delete]
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	overrideDeleter OverrideDeleter,
+) gin.HandlerFunc {
+	const op = "handler.override.delete.New"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		if err := overrideDeleter.Delete(ctx, c.Param("name"), c.Param("field")); err != nil {
+			if errors.Is(err, overrideService.ErrOverrideNotFound) {
+				c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "override not found"})
+				return
+			}
+
+			log.Error("failed to delete override", sl.Err(err))
+
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,55 @@
+package list
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/lib/logger/sl"
+	"person-info/internal/transport/dto"
+)
+
+type OverridesProvider interface {
+	Overrides(ctx context.Context, name string) ([]*dto.NameOverrideResponse, error)
+}
+
+// @Summary List name overrides
+// @Description Returns the overrides for a name, or all of them when name is omitted
+// @Tags /admin
+// @Produce json
+// @Param name query string false "Name"
+// @Success 200 {array} dto.NameOverrideResponse "Name overrides"
+// @Failure 400 {object} dto.ErrorResponse "Invalid query"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/overrides [get]
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	provider OverridesProvider,
+) gin.HandlerFunc {
+	const op = "handler.override.list.New"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		var filter dto.OverridesFilter
+		if err := c.ShouldBindQuery(&filter); err != nil {
+			log.Error("failed to parse query", sl.Err(err))
+
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid query"})
+			return
+		}
+
+		overrides, err := provider.Overrides(ctx, filter.Name)
+		if err != nil {
+			log.Error("failed to list overrides", sl.Err(err))
+
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, overrides)
+	}
+}
@@ -0,0 +1,75 @@
+package save
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/lib/logger/sl"
+	overrideService "person-info/internal/service/override"
+	"person-info/internal/transport/dto"
+)
+
+type OverrideSaver interface {
+	Save(ctx context.Context, name, field, value string) (*dto.NameOverrideResponse, error)
+}
+
+// @Summary Set a name override
+// @Description Creates or replaces the answer given for a name ahead of the external providers.
+// @Description The override is pinned, edits of people with the name no longer change it.
+// @Tags /admin
+// @Accept json
+// @Produce json
+// @Param name path string true "Name"
+// @Param field path string true "Field" Enums(age, gender, nationality)
+// @Param input body dto.SaveOverrideRequest true "Override value"
+// @Success 200 {object} dto.NameOverrideResponse "Saved override"
+// @Failure 400 {object} dto.ErrorResponse "Invalid field or value"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/overrides/{name}/{field} [put]
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	overrideSaver OverrideSaver,
+) gin.HandlerFunc {
+	const op = "handler.override.save.New"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		var req dto.SaveOverrideRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				log.Error("request body is empty")
+
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "request body is empty"})
+				return
+			}
+			log.Error("failed to decode request body", sl.Err(err))
+
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+			return
+		}
+
+		override, err := overrideSaver.Save(ctx, c.Param("name"), c.Param("field"), req.Value)
+		if err != nil {
+			switch {
+			case errors.Is(err, overrideService.ErrInvalidField):
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "field must be one of age, gender, nationality"})
+			case errors.Is(err, overrideService.ErrInvalidValue):
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+			default:
+				log.Error("failed to save override", sl.Err(err))
+
+				c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, override)
+	}
+}
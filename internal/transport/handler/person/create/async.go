@@ -0,0 +1,58 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/lib/logger/sl"
+	"person-info/internal/transport/dto"
+)
+
+type PersonAccepter interface {
+	Accept(ctx context.Context, person *dto.CreatePersonRequest) (*dto.AcceptedResponse, error)
+}
+
+// NewAsync replaces New when enrichment runs in the background, see the 202 response of POST /people
+func NewAsync(
+	ctx context.Context,
+	log *slog.Logger,
+	personAccepter PersonAccepter,
+) gin.HandlerFunc {
+	const op = "handler.person.create.NewAsync"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		var res dto.CreatePersonRequest
+		if err := c.ShouldBindJSON(&res); err != nil {
+			if errors.Is(err, io.EOF) {
+				log.Error("request body is empty")
+
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "request body is empty"})
+				return
+			}
+			log.Error("failed to decode request body", sl.Err(err))
+
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+			return
+		}
+
+		log.Debug("request body received", slog.Any("request", res))
+
+		accepted, err := personAccepter.Accept(ctx, &res)
+		if err != nil {
+			log.Error("failed to accept person", sl.Err(err))
+
+			c.JSON(errorResponse(err))
+			return
+		}
+
+		c.Header("Location", location(accepted.ID))
+		c.JSON(http.StatusAccepted, accepted)
+	}
+}
@@ -0,0 +1,85 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/lib/logger/sl"
+	personSevice "person-info/internal/service/person"
+	"person-info/internal/transport/dto"
+	"person-info/internal/transport/handler/providererr"
+)
+
+type PeopleSaver interface {
+	SaveBatch(ctx context.Context, people []*dto.CreatePersonRequest) ([]*personSevice.SaveResult, error)
+}
+
+// @Summary Save people in bulk
+// @Description Saves up to 100 people, predictions for distinct names are fetched in batches.
+// @Description Every person gets its own status, a failure to save one person does not undo the others.
+// @Tags /people
+// @Accept json
+// @Produce json
+// @Param input body dto.CreatePeopleRequest true "People request data"
+// @Success 200 {object} []dto.BatchPersonResponse "Per-person results"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request data"
+// @Failure 429 {object} dto.ErrorResponse "Prediction provider rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Failure 502 {object} dto.ErrorResponse "Prediction provider failed"
+// @Failure 503 {object} dto.ErrorResponse "Prediction provider unavailable"
+// @Failure 504 {object} dto.ErrorResponse "Prediction provider timed out"
+// @Router /people/batch [post]
+func NewBatch(
+	ctx context.Context,
+	log *slog.Logger,
+	peopleSaver PeopleSaver,
+) gin.HandlerFunc {
+	const op = "handler.person.create.NewBatch"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		var req dto.CreatePeopleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				log.Error("request body is empty")
+
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "request body is empty"})
+				return
+			}
+			log.Error("failed to decode request body", sl.Err(err))
+
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+			return
+		}
+
+		log.Debug("request body received", slog.Int("people", len(req.People)))
+
+		results, err := peopleSaver.SaveBatch(ctx, req.People)
+		if err != nil {
+			log.Error("failed to create people", sl.Err(err))
+
+			providererr.SetRetryAfter(c, err)
+			c.JSON(errorResponse(err))
+			return
+		}
+
+		resp := make([]dto.BatchPersonResponse, len(results))
+		for i, result := range results {
+			if result.Err != nil {
+				status, errResp := errorResponse(result.Err)
+				resp[i] = dto.BatchPersonResponse{Status: status, Error: errResp.Error}
+				continue
+			}
+
+			resp[i] = dto.BatchPersonResponse{Status: http.StatusCreated, Person: result.Person}
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
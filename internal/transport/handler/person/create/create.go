@@ -3,16 +3,17 @@ package create
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
-	personClient "person-info/internal/client/person"
 	"person-info/internal/lib/logger/sl"
 	personSevice "person-info/internal/service/person"
 	"person-info/internal/transport/dto"
+	"person-info/internal/transport/handler/providererr"
 )
 
 type PersonSaver interface {
@@ -26,9 +27,16 @@ type PersonSaver interface {
 // @Produce json
 // @Param input body dto.CreatePersonRequest true "Person request data"
 // @Success 201 {object} dto.PersonResponse "Successfully saved person"
+// @Success 202 {object} dto.AcceptedResponse "Person accepted for background enrichment in async mode"
+// @Header 201,202 {string} Location "URL of the person, /people/{id}"
 // @Failure 400 {object} dto.ErrorResponse "Invalid request data"
 // @Failure 409 {object} dto.ErrorResponse "Person already exists"
+// @Failure 422 {object} dto.ErrorResponse "Invalid name or prediction confidence is too low"
+// @Failure 429 {object} dto.ErrorResponse "Prediction provider rate limit exceeded"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Failure 502 {object} dto.ErrorResponse "Prediction provider failed"
+// @Failure 503 {object} dto.ErrorResponse "Prediction provider unavailable"
+// @Failure 504 {object} dto.ErrorResponse "Prediction provider timed out"
 // @Router /people [post]
 func New(
 	ctx context.Context,
@@ -60,17 +68,31 @@ func New(
 		if err != nil {
 			log.Error("failed to create person", sl.Err(err))
 
-			switch {
-			case errors.Is(err, personSevice.ErrPersonExists):
-				c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "person already exists"})
-			case errors.Is(err, personClient.ErrInvalidName):
-				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid name"})
-			default:
-				c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"})
-			}
+			providererr.SetRetryAfter(c, err)
+			c.JSON(errorResponse(err))
 			return
 		}
 
+		c.Header("Location", location(person.ID))
 		c.JSON(http.StatusCreated, person)
 	}
 }
+
+func location(id int64) string {
+	return fmt.Sprintf("/people/%d", id)
+}
+
+func errorResponse(err error) (int, dto.ErrorResponse) {
+	switch {
+	case errors.Is(err, personSevice.ErrPersonExists):
+		return http.StatusConflict, dto.ErrorResponse{Error: "person already exists"}
+	case errors.Is(err, personSevice.ErrLowConfidence):
+		return http.StatusUnprocessableEntity, dto.ErrorResponse{Error: "prediction confidence is too low"}
+	}
+
+	if status, resp, ok := providererr.Response(err); ok {
+		return status, resp
+	}
+
+	return http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"}
+}
@@ -0,0 +1,170 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/lib/logger/sl"
+	personSevice "person-info/internal/service/person"
+	"person-info/internal/transport/dto"
+	"person-info/internal/transport/handler/person/read"
+	"person-info/internal/transport/handler/providererr"
+)
+
+// maxBulkSize caps people re-enriched within one request, larger runs belong to cmd/enrich
+const maxBulkSize = 100
+
+type PersonEnricher interface {
+	Enrich(ctx context.Context, id int64, force bool) (*dto.EnrichmentDiffResponse, error)
+}
+
+type PeopleEnricher interface {
+	EnrichPeople(ctx context.Context,
+		filters *dto.PeopleFilters,
+		pagination *dto.Pagination,
+		sorting *dto.SortOptions,
+		force bool,
+	) (*dto.BulkEnrichmentResponse, error)
+}
+
+// @Summary Re-enrich a person
+// @Description Re-runs the prediction providers for a person, attributes edited by hand are kept unless forced
+// @Tags /people
+// @Produce json
+// @Param id path int true "Person ID"
+// @Param options query dto.EnrichOptions false "Options"
+// @Success 200 {object} dto.EnrichmentDiffResponse "Changed attributes"
+// @Failure 400 {object} dto.ErrorResponse "Missing or invalid id"
+// @Failure 404 {object} dto.ErrorResponse "Person not found"
+// @Failure 429 {object} dto.ErrorResponse "Prediction provider rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Failure 502 {object} dto.ErrorResponse "Prediction provider failed"
+// @Failure 503 {object} dto.ErrorResponse "Prediction provider unavailable"
+// @Failure 504 {object} dto.ErrorResponse "Prediction provider timed out"
+// @Router /people/{id}/enrich [post]
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	enricher PersonEnricher,
+) gin.HandlerFunc {
+	const op = "handler.person.enrich.New"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			log.Error("failed parse id", sl.Err(err))
+
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid id"})
+			return
+		}
+
+		var opts dto.EnrichOptions
+		if err := c.ShouldBindQuery(&opts); err != nil {
+			log.Error("failed to bind options", sl.Err(err))
+
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid options: " + err.Error()})
+			return
+		}
+
+		diff, err := enricher.Enrich(ctx, id, opts.Force)
+		if err != nil {
+			if errors.Is(err, personSevice.ErrPersonNotFound) {
+				c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "person not found"})
+				return
+			}
+
+			log.Error("failed to re-enrich person", sl.Err(err))
+
+			if status, resp, ok := providererr.Response(err); ok {
+				providererr.SetRetryAfter(c, err)
+				c.JSON(status, resp)
+				return
+			}
+
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, diff)
+	}
+}
+
+// @Summary Re-enrich people
+// @Description Re-runs the prediction providers for a page of people selected like in GET /people.
+// @Description The page size is required and at most 100.
+// @Tags /people
+// @Produce json
+// @Param filters query dto.PeopleFilters false "Filters"
+// @Param pagination query dto.Pagination false "Pagination"
+// @Param sort query dto.SortOptions false "Sorting"
+// @Param options query dto.EnrichOptions false "Options"
+// @Success 200 {object} dto.BulkEnrichmentResponse "Changed attributes per person"
+// @Failure 400 {object} dto.ErrorResponse "Invalid filters or missing page size"
+// @Failure 429 {object} dto.ErrorResponse "Prediction provider rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Failure 502 {object} dto.ErrorResponse "Prediction provider failed"
+// @Failure 503 {object} dto.ErrorResponse "Prediction provider unavailable"
+// @Failure 504 {object} dto.ErrorResponse "Prediction provider timed out"
+// @Router /people/enrich [post]
+func NewBulk(
+	ctx context.Context,
+	log *slog.Logger,
+	enricher PeopleEnricher,
+) gin.HandlerFunc {
+	const op = "handler.person.enrich.NewBulk"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		var (
+			filters    dto.PeopleFilters
+			pagination dto.Pagination
+			sort       dto.SortOptions
+		)
+
+		if !read.ParseQueryWithValidation(c, log, &filters, &pagination, &sort) {
+			return
+		}
+
+		if pagination.Size < 1 || pagination.Size > maxBulkSize {
+			log.Error("invalid page size", slog.Int("size", pagination.Size))
+
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: fmt.Sprintf("size is required and must be between 1 and %d", maxBulkSize),
+			})
+			return
+		}
+
+		var opts dto.EnrichOptions
+		if err := c.ShouldBindQuery(&opts); err != nil {
+			log.Error("failed to bind options", sl.Err(err))
+
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid options: " + err.Error()})
+			return
+		}
+
+		resp, err := enricher.EnrichPeople(ctx, &filters, &pagination, &sort, opts.Force)
+		if err != nil {
+			log.Error("failed to re-enrich people", sl.Err(err))
+
+			if status, resp, ok := providererr.Response(err); ok {
+				providererr.SetRetryAfter(c, err)
+				c.JSON(status, resp)
+				return
+			}
+
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,60 @@
+package enrich
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"person-info/internal/transport/dto"
+)
+
+type stubPeopleEnricher struct {
+	size int
+}
+
+func (s *stubPeopleEnricher) EnrichPeople(_ context.Context,
+	_ *dto.PeopleFilters,
+	pagination *dto.Pagination,
+	_ *dto.SortOptions,
+	_ bool,
+) (*dto.BulkEnrichmentResponse, error) {
+	s.size = pagination.Size
+	return &dto.BulkEnrichmentResponse{}, nil
+}
+
+func TestNewBulkPageSize(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "missing size", query: "", want: http.StatusBadRequest},
+		{name: "size above the cap", query: "?size=101", want: http.StatusBadRequest},
+		{name: "size within the cap", query: "?size=100&page=2", want: http.StatusOK},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enricher := &stubPeopleEnricher{}
+
+			r := gin.New()
+			r.POST("/people/enrich", NewBulk(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), enricher))
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/people/enrich"+tt.query, nil))
+
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+			if tt.want == http.StatusOK {
+				assert.Equal(t, maxBulkSize, enricher.size)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package provenance
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/lib/logger/sl"
+	personSevice "person-info/internal/service/person"
+	"person-info/internal/transport/dto"
+)
+
+type ProvenanceProvider interface {
+	Provenance(ctx context.Context, id int64) (*dto.ProvenanceResponse, error)
+}
+
+// @Summary Get person provenance
+// @Description Tells the source, confidence and time each predicted attribute was set with
+// @Tags /people
+// @Produce json
+// @Param id path int true "Person ID"
+// @Success 200 {object} dto.ProvenanceResponse "Provenance per attribute"
+// @Failure 400 {object} dto.ErrorResponse "Missing or invalid id"
+// @Failure 404 {object} dto.ErrorResponse "Person not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /people/{id}/provenance [get]
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	provider ProvenanceProvider,
+) gin.HandlerFunc {
+	const op = "handler.person.provenance.New"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			log.Error("failed parse id", sl.Err(err))
+
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid id"})
+			return
+		}
+
+		provenance, err := provider.Provenance(ctx, id)
+		if err != nil {
+			if errors.Is(err, personSevice.ErrPersonNotFound) {
+				c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "person not found"})
+				return
+			}
+
+			log.Error("failed to get provenance", sl.Err(err))
+
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, provenance)
+	}
+}
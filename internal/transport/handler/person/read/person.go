@@ -0,0 +1,64 @@
+package read
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/lib/logger/sl"
+	personSevice "person-info/internal/service/person"
+	"person-info/internal/transport/dto"
+)
+
+type PersonProvider interface {
+	Person(ctx context.Context, id int64) (*dto.PersonDetailsResponse, error)
+}
+
+// @Summary Get a person
+// @Description Gets a person by id with prediction confidence, provenance and links to related resources
+// @Tags /people
+// @Produce json
+// @Param id path int true "Person ID"
+// @Success 200 {object} dto.PersonDetailsResponse "Person details"
+// @Failure 400 {object} dto.ErrorResponse "Missing or invalid id"
+// @Failure 404 {object} dto.ErrorResponse "Person not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /people/{id} [get]
+func NewPerson(
+	ctx context.Context,
+	log *slog.Logger,
+	personProvider PersonProvider,
+) gin.HandlerFunc {
+	const op = "handler.person.read.NewPerson"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			log.Error("failed parse id", sl.Err(err))
+
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid id"})
+			return
+		}
+
+		person, err := personProvider.Person(ctx, id)
+		if err != nil {
+			if errors.Is(err, personSevice.ErrPersonNotFound) {
+				c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "person not found"})
+				return
+			}
+
+			log.Error("failed to get person", sl.Err(err))
+
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, person)
+	}
+}
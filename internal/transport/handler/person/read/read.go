@@ -46,7 +46,7 @@ func New(
 			sort       dto.SortOptions
 		)
 
-		if !parseQueryWithValidation(c, log, &filters, &pagination, &sort) {
+		if !ParseQueryWithValidation(c, log, &filters, &pagination, &sort) {
 			return
 		}
 
@@ -62,7 +62,8 @@ func New(
 	}
 }
 
-func parseQueryWithValidation(
+// ParseQueryWithValidation binds people filters, pagination and sorting, responding 400 on failure
+func ParseQueryWithValidation(
 	c *gin.Context,
 	log *slog.Logger,
 	filters *dto.PeopleFilters,
@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/lib/logger/sl"
+	personSevice "person-info/internal/service/person"
+	"person-info/internal/transport/dto"
+)
+
+type EnrichmentRetrier interface {
+	Retry(ctx context.Context, id int64) (*dto.AcceptedResponse, error)
+}
+
+// @Summary Retry failed enrichment
+// @Description Queues enrichment of a person whose enrichment has failed
+// @Tags /people
+// @Produce json
+// @Param id path int true "Person ID"
+// @Success 202 {object} dto.AcceptedResponse "Enrichment queued"
+// @Failure 400 {object} dto.ErrorResponse "Missing or invalid id"
+// @Failure 404 {object} dto.ErrorResponse "Person not found"
+// @Failure 409 {object} dto.ErrorResponse "Enrichment has not failed"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /people/{id}/retry [post]
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	retrier EnrichmentRetrier,
+) gin.HandlerFunc {
+	const op = "handler.person.retry.New"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			log.Error("failed parse id", sl.Err(err))
+
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid id"})
+			return
+		}
+
+		accepted, err := retrier.Retry(ctx, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, personSevice.ErrPersonNotFound):
+				c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "person not found"})
+			case errors.Is(err, personSevice.ErrNotRetryable):
+				c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "enrichment has not failed"})
+			default:
+				log.Error("failed to retry enrichment", sl.Err(err))
+
+				c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusAccepted, accepted)
+	}
+}
@@ -49,8 +49,8 @@ func New(
 			return
 		}
 
-		var req *dto.UpdatePersonRequest
-		if err := c.ShouldBindJSON(req); err != nil {
+		var req dto.UpdatePersonRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
 			if errors.Is(err, io.EOF) {
 				log.Error("request body is empty")
 
@@ -63,7 +63,7 @@ func New(
 			return
 		}
 
-		updatedPerson, err := personUpdater.Update(ctx, id, req)
+		updatedPerson, err := personUpdater.Update(ctx, id, &req)
 		if err != nil {
 			switch {
 			case errors.Is(err, personService.ErrPersonNotFound):
@@ -0,0 +1,97 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	personService "person-info/internal/service/person"
+	"person-info/internal/transport/dto"
+)
+
+type stubUpdater struct {
+	id  int64
+	req *dto.UpdatePersonRequest
+	err error
+}
+
+func (s *stubUpdater) Update(_ context.Context, id int64, req *dto.UpdatePersonRequest) (*dto.PersonResponse, error) {
+	s.id, s.req = id, req
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &dto.PersonResponse{ID: id, Name: "Anna", Gender: req.Gender}, nil
+}
+
+func serve(updater PersonUpdater, target, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.PATCH("/people/:id", New(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), updater))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestUpdate(t *testing.T) {
+	updater := &stubUpdater{}
+
+	w := serve(updater, "/people/7", `{"gender":"female","nationality":"RU"}`)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, int64(7), updater.id)
+	require.NotNil(t, updater.req)
+	assert.Equal(t, "female", updater.req.Gender)
+	assert.Equal(t, "RU", updater.req.Nationality)
+	assert.Contains(t, w.Body.String(), `"gender":"female"`)
+}
+
+func TestUpdateErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		err    error
+		want   int
+	}{
+		{name: "invalid id", target: "/people/abc", body: `{"age":30}`, want: http.StatusBadRequest},
+		{name: "empty body", target: "/people/1", want: http.StatusBadRequest},
+		{name: "malformed body", target: "/people/1", body: `{"age":"old"}`, want: http.StatusBadRequest},
+		{
+			name:   "not found",
+			target: "/people/1",
+			body:   `{"age":30}`,
+			err:    fmt.Errorf("service: %w", personService.ErrPersonNotFound),
+			want:   http.StatusNotFound,
+		},
+		{
+			name:   "no updated fields",
+			target: "/people/1",
+			body:   `{}`,
+			err:    fmt.Errorf("service: %w", personService.ErrNoUpdatedFields),
+			want:   http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(&stubUpdater{err: tt.err}, tt.target, tt.body)
+
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package preview
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/lib/logger/sl"
+	"person-info/internal/transport/dto"
+	"person-info/internal/transport/handler/providererr"
+)
+
+type PredictionPreviewer interface {
+	Preview(ctx context.Context, req *dto.PredictionRequest) (*dto.PredictionResponse, error)
+}
+
+// @Summary Preview predictions
+// @Description Predicts age, gender and nationality like saving a person would, without saving anything
+// @Tags /predictions
+// @Produce json
+// @Param name query string true "Name"
+// @Param surname query string false "Surname"
+// @Param patronymic query string false "Patronymic"
+// @Param country_hint query string false "Country hint, ISO 3166-1 alpha-2"
+// @Success 200 {object} dto.PredictionResponse "Predicted attributes"
+// @Failure 400 {object} dto.ErrorResponse "Invalid query"
+// @Failure 422 {object} dto.ErrorResponse "Invalid name"
+// @Failure 429 {object} dto.ErrorResponse "Prediction provider rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Failure 502 {object} dto.ErrorResponse "Prediction provider failed"
+// @Failure 503 {object} dto.ErrorResponse "Prediction provider unavailable"
+// @Failure 504 {object} dto.ErrorResponse "Prediction provider timed out"
+// @Router /predictions [get]
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	previewer PredictionPreviewer,
+) gin.HandlerFunc {
+	const op = "handler.prediction.preview.New"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		var req dto.PredictionRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			log.Error("failed to parse query", sl.Err(err))
+
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid query"})
+			return
+		}
+
+		prediction, err := previewer.Preview(ctx, &req)
+		if err != nil {
+			log.Error("failed to preview predictions", sl.Err(err))
+
+			providererr.SetRetryAfter(c, err)
+
+			if status, resp, ok := providererr.Response(err); ok {
+				c.JSON(status, resp)
+				return
+			}
+
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, prediction)
+	}
+}
@@ -0,0 +1,56 @@
+package usage
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"person-info/internal/domain/model"
+	"person-info/internal/lib/logger/sl"
+	"person-info/internal/transport/dto"
+)
+
+type UsageProvider interface {
+	Usage(ctx context.Context) (*model.ProviderUsage, error)
+	CircuitState() string
+}
+
+// @Summary Provider usage
+// @Description Reports today's quota usage of each prediction provider, a degraded provider is skipped until its quota resets
+// @Tags /admin
+// @Produce json
+// @Success 200 {array} dto.ProviderUsageResponse "Usage per provider"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/providers [get]
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	providers map[string]UsageProvider,
+) gin.HandlerFunc {
+	const op = "handler.provider.usage.New"
+
+	return func(c *gin.Context) {
+		log := log.With(slog.String("op", op))
+
+		resp := make([]*dto.ProviderUsageResponse, 0, len(providers))
+		for _, name := range slices.Sorted(maps.Keys(providers)) {
+			provider := providers[name]
+
+			usage, err := provider.Usage(ctx)
+			if err != nil {
+				log.Error("failed to get provider usage", slog.String("provider", name), sl.Err(err))
+
+				c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal server error"})
+				return
+			}
+
+			resp = append(resp, dto.ToProviderUsageResponse(usage, provider.CircuitState()))
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,41 @@
+package providererr
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	personClient "person-info/internal/client/person"
+	"person-info/internal/transport/dto"
+)
+
+// Response maps a prediction provider failure to an HTTP status and body,
+// ok is false if err is not a provider error
+func Response(err error) (status int, resp dto.ErrorResponse, ok bool) {
+	switch {
+	case errors.Is(err, personClient.ErrInvalidName):
+		return http.StatusUnprocessableEntity, dto.ErrorResponse{Error: "invalid name"}, true
+	case errors.Is(err, personClient.ErrRateLimited):
+		return http.StatusTooManyRequests, dto.ErrorResponse{Error: "prediction provider rate limit exceeded"}, true
+	case errors.Is(err, personClient.ErrCircuitOpen), errors.Is(err, personClient.ErrProviderDisabled):
+		return http.StatusServiceUnavailable, dto.ErrorResponse{Error: "prediction provider unavailable"}, true
+	case errors.Is(err, personClient.ErrTimeout):
+		return http.StatusGatewayTimeout, dto.ErrorResponse{Error: "prediction provider timed out"}, true
+	case errors.Is(err, personClient.ErrUpstreamUnavailable):
+		return http.StatusBadGateway, dto.ErrorResponse{Error: "prediction provider failed"}, true
+	default:
+		return 0, dto.ErrorResponse{}, false
+	}
+}
+
+// SetRetryAfter sets the Retry-After header when a provider is rate limited
+func SetRetryAfter(c *gin.Context, err error) {
+	var rateLimitErr *personClient.RateLimitError
+	if errors.As(err, &rateLimitErr) && rateLimitErr.RetryAfter > 0 {
+		seconds := int(math.Ceil(rateLimitErr.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+	}
+}
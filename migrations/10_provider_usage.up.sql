@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS provider_usage (
+    provider VARCHAR(32) NOT NULL,
+    day DATE NOT NULL,
+    calls INT NOT NULL DEFAULT 0,
+    names INT NOT NULL DEFAULT 0,
+    quota_limit INT NOT NULL DEFAULT 0,
+    remaining INT,
+    reset_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, day)
+);
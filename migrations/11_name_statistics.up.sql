@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS name_statistics (
+    name VARCHAR(255) NOT NULL,
+    field VARCHAR(16) NOT NULL,
+    value VARCHAR(32) NOT NULL,
+    people INT NOT NULL,
+    refreshed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (name, field, value)
+);
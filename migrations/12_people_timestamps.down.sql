@@ -0,0 +1,3 @@
+ALTER TABLE people
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,2 @@
+ALTER TABLE name_overrides
+    DROP COLUMN IF EXISTS pinned;
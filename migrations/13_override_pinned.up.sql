@@ -0,0 +1,5 @@
+ALTER TABLE name_overrides
+    ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- overrides nobody corrected were set through the admin API
+UPDATE name_overrides SET pinned = TRUE WHERE corrections = 0;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS name_predictions_fetched_at_idx;
+
+DROP TABLE IF EXISTS name_predictions;
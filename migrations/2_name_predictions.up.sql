@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS name_predictions (
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    payload JSONB NOT NULL,
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (name, kind)
+);
+
+CREATE INDEX IF NOT EXISTS name_predictions_fetched_at_idx ON name_predictions (fetched_at);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS person_nationalities;
+
+ALTER TABLE people
+    DROP COLUMN IF EXISTS age_count,
+    DROP COLUMN IF EXISTS gender_probability,
+    DROP COLUMN IF EXISTS gender_count,
+    DROP COLUMN IF EXISTS nationality_count;
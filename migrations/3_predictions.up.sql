@@ -0,0 +1,13 @@
+ALTER TABLE people
+    ADD COLUMN IF NOT EXISTS age_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS gender_probability DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS gender_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS nationality_count INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS person_nationalities (
+    person_id INT NOT NULL REFERENCES people (id) ON DELETE CASCADE,
+    rank INT NOT NULL,
+    country_id VARCHAR(32) NOT NULL,
+    probability DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (person_id, rank)
+);
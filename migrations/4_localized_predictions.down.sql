@@ -0,0 +1,6 @@
+DELETE FROM name_predictions WHERE country_id <> '';
+
+ALTER TABLE name_predictions DROP CONSTRAINT IF EXISTS name_predictions_pkey;
+ALTER TABLE name_predictions ADD PRIMARY KEY (name, kind);
+
+ALTER TABLE name_predictions DROP COLUMN IF EXISTS country_id;
@@ -0,0 +1,5 @@
+ALTER TABLE name_predictions
+    ADD COLUMN IF NOT EXISTS country_id VARCHAR(8) NOT NULL DEFAULT '';
+
+ALTER TABLE name_predictions DROP CONSTRAINT IF EXISTS name_predictions_pkey;
+ALTER TABLE name_predictions ADD PRIMARY KEY (name, kind, country_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS people_low_confidence_idx;
+
+ALTER TABLE people
+    DROP COLUMN IF EXISTS age_low_confidence,
+    DROP COLUMN IF EXISTS gender_low_confidence,
+    DROP COLUMN IF EXISTS nationality_low_confidence;
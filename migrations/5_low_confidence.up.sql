@@ -0,0 +1,7 @@
+ALTER TABLE people
+    ADD COLUMN IF NOT EXISTS age_low_confidence BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS gender_low_confidence BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS nationality_low_confidence BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS people_low_confidence_idx ON people (id)
+    WHERE age_low_confidence OR gender_low_confidence OR nationality_low_confidence;
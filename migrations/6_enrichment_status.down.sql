@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS people_enrichment_status_idx;
+
+ALTER TABLE people
+    DROP COLUMN IF EXISTS country_hint,
+    DROP COLUMN IF EXISTS enrichment_status,
+    DROP COLUMN IF EXISTS enrichment_attempts,
+    DROP COLUMN IF EXISTS enrichment_error;
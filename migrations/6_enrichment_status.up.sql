@@ -0,0 +1,8 @@
+ALTER TABLE people
+    ADD COLUMN IF NOT EXISTS country_hint VARCHAR(8) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS enrichment_status VARCHAR(16) NOT NULL DEFAULT 'done',
+    ADD COLUMN IF NOT EXISTS enrichment_attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS enrichment_error TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS people_enrichment_status_idx ON people (enrichment_status)
+    WHERE enrichment_status <> 'done';
@@ -0,0 +1,2 @@
+ALTER TABLE people
+    DROP COLUMN IF EXISTS manual_fields;
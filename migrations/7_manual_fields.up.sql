@@ -0,0 +1,2 @@
+ALTER TABLE people
+    ADD COLUMN IF NOT EXISTS manual_fields TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,13 @@
+ALTER TABLE people
+    ADD COLUMN IF NOT EXISTS manual_fields TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE people p SET manual_fields = m.fields
+FROM (
+    SELECT person_id, array_agg(field) AS fields
+    FROM person_provenance
+    WHERE source = 'manual'
+    GROUP BY person_id
+) m
+WHERE m.person_id = p.id;
+
+DROP TABLE IF EXISTS person_provenance;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS person_provenance (
+    person_id INT NOT NULL REFERENCES people (id) ON DELETE CASCADE,
+    field VARCHAR(16) NOT NULL,
+    source VARCHAR(32) NOT NULL,
+    confidence DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (person_id, field)
+);
+
+INSERT INTO person_provenance (person_id, field, source, confidence)
+SELECT p.id, f.field,
+       CASE WHEN f.field = ANY (p.manual_fields) THEN 'manual' ELSE 'import' END,
+       CASE WHEN f.field = ANY (p.manual_fields) THEN 1 END
+FROM people p
+CROSS JOIN (VALUES ('age'), ('gender'), ('nationality')) AS f (field)
+WHERE p.enrichment_status = 'done'
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE people DROP COLUMN IF EXISTS manual_fields;
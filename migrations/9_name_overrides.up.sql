@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS name_overrides (
+    name VARCHAR(255) NOT NULL,
+    field VARCHAR(16) NOT NULL,
+    value VARCHAR(32) NOT NULL,
+    corrections INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (name, field)
+);